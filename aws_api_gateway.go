@@ -0,0 +1,62 @@
+package restfulspec
+
+import (
+	"strings"
+
+	restful "github.com/emicklei/go-restful/v3"
+)
+
+// AWSAPIGatewayConfig describes the AWS API Gateway Lambda integration of all operations.
+// It generates the x-amazon-apigateway-integration (and x-amazon-apigateway-auth) extension of each operation.
+type AWSAPIGatewayConfig struct {
+	// LambdaARNTemplate is the ARN of the Lambda function of an operation, in which {method}, {path} and {operation}
+	// are replaced by the method, path and operation id of the route,
+	// e.g. arn:aws:lambda:eu-west-1:123456789012:function:{operation}
+	LambdaARNTemplate string
+	// IntegrationType of the integration, aws_proxy if empty
+	IntegrationType string
+	// AuthorizationType of the x-amazon-apigateway-auth extension, e.g. AWS_IAM; no extension if empty
+	AuthorizationType string
+	// PassthroughBehavior of the integration, e.g. WHEN_NO_MATCH, WHEN_NO_TEMPLATES or NEVER; omitted if empty
+	PassthroughBehavior string
+	// CredentialsARN is the ARN of the IAM role that API Gateway assumes to invoke the function; omitted if empty
+	CredentialsARN string
+}
+
+// extensions returns the API Gateway extensions of the operation of the route at the path.
+func (c AWSAPIGatewayConfig) extensions(path string, r restful.Route) map[string]interface{} {
+	lambdaARN := strings.NewReplacer(
+		"{method}", r.Method,
+		"{path}", path,
+		"{operation}", r.Operation,
+	).Replace(c.LambdaARNTemplate)
+	integration := map[string]interface{}{
+		"type":       "aws_proxy",
+		"httpMethod": "POST", // Lambda functions are always invoked with POST
+		"uri":        lambdaInvocationURI(lambdaARN),
+	}
+	if c.IntegrationType != "" {
+		integration["type"] = c.IntegrationType
+	}
+	if c.PassthroughBehavior != "" {
+		integration["passthroughBehavior"] = c.PassthroughBehavior
+	}
+	if c.CredentialsARN != "" {
+		integration["credentials"] = c.CredentialsARN
+	}
+	extensions := map[string]interface{}{"x-amazon-apigateway-integration": integration}
+	if c.AuthorizationType != "" {
+		extensions["x-amazon-apigateway-auth"] = map[string]interface{}{"type": c.AuthorizationType}
+	}
+	return extensions
+}
+
+// lambdaInvocationURI returns the API Gateway uri that invokes the Lambda function,
+// in the region of the function, e.g. arn:aws:lambda:eu-west-1:123456789012:function:name
+func lambdaInvocationURI(lambdaARN string) string {
+	region := ""
+	if parts := strings.Split(lambdaARN, ":"); len(parts) > 3 {
+		region = parts[3]
+	}
+	return "arn:aws:apigateway:" + region + ":lambda:path/2015-03-31/functions/" + lambdaARN + "/invocations"
+}
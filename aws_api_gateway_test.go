@@ -0,0 +1,45 @@
+package restfulspec
+
+import (
+	"encoding/json"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+)
+
+func TestAWSAPIGatewayConfig(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/users")
+	ws.Route(ws.GET("/{id}").To(dummy).Operation("getUser"))
+	ws.Route(ws.DELETE("/{id}").To(dummy).Operation("deleteUser"))
+
+	cfg := Config{
+		AWSAPIGatewayConfig: &AWSAPIGatewayConfig{
+			LambdaARNTemplate:   "arn:aws:lambda:eu-west-1:123456789012:function:{operation}",
+			AuthorizationType:   "AWS_IAM",
+			PassthroughBehavior: "WHEN_NO_MATCH",
+		},
+		PathExtensionsFunc: func(path string, method string) map[string]interface{} {
+			if method == "DELETE" {
+				return map[string]interface{}{"x-amazon-apigateway-auth": map[string]interface{}{"type": "NONE"}}
+			}
+			return nil
+		},
+	}
+	p := buildPaths(ws, cfg)
+	t.Log(asJSON(p))
+
+	item := p.Paths["/users/{id}"]
+	data, err := json.Marshal(item.Get.Extensions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"x-amazon-apigateway-auth":{"type":"AWS_IAM"},"x-amazon-apigateway-integration":{"httpMethod":"POST","passthroughBehavior":"WHEN_NO_MATCH","type":"aws_proxy","uri":"arn:aws:apigateway:eu-west-1:lambda:path/2015-03-31/functions/arn:aws:lambda:eu-west-1:123456789012:function:getUser/invocations"}}`
+	if string(data) != expected {
+		t.Errorf("got %s want %s", data, expected)
+	}
+	auth := item.Delete.Extensions["x-amazon-apigateway-auth"].(map[string]interface{})
+	if got, want := auth["type"], "NONE"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
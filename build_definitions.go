@@ -2,6 +2,7 @@ package restfulspec
 
 import (
 	"reflect"
+	"sort"
 
 	restful "github.com/emicklei/go-restful/v3"
 	"github.com/go-openapi/spec"
@@ -12,22 +13,148 @@ func buildDefinitions(ws *restful.WebService, cfg Config) (definitions spec.Defi
 	for _, each := range ws.Routes() {
 		addDefinitionsFromRouteTo(each, cfg, definitions)
 	}
+	if cfg.ErrorType != nil {
+		builder := definitionBuilder{Definitions: definitions, Config: cfg}
+		builder.addModelFrom(cfg.ErrorType)
+	}
 	return
 }
 
 func addDefinitionsFromRouteTo(r restful.Route, cfg Config, d spec.Definitions) {
 	builder := definitionBuilder{Definitions: d, Config: cfg}
-	if r.ReadSample != nil {
+	if r.ReadSample != nil && !isFormDataSample(r.ReadSample) {
 		builder.addModel(reflect.TypeOf(r.ReadSample), "")
 	}
+	builder.buildParameterEnum(r, d)
+	if producesBinary(r.Produces) {
+		// responses are documented as binary payloads
+		return
+	}
 	if r.WriteSample != nil {
 		builder.addModel(reflect.TypeOf(r.WriteSample), "")
 	}
-	builder.buildParameterEnum(r, d)
 	for _, v := range r.ResponseErrors {
 		if v.Model == nil {
 			continue
 		}
 		builder.addModel(reflect.TypeOf(v.Model), "")
 	}
+	for _, model := range responseModels(r) {
+		if model == nil {
+			continue
+		}
+		builder.addModel(reflect.TypeOf(model), "")
+	}
+	if producesXML(r.Produces) {
+		visited := map[string]bool{}
+		if r.WriteSample != nil {
+			builder.addXMLNames(reflect.TypeOf(r.WriteSample), visited)
+		}
+		for _, v := range r.ResponseErrors {
+			if v.Model != nil {
+				builder.addXMLNames(reflect.TypeOf(v.Model), visited)
+			}
+		}
+		for _, model := range responseModels(r) {
+			if model != nil {
+				builder.addXMLNames(reflect.TypeOf(model), visited)
+			}
+		}
+	}
+}
+
+// addXMLNames sets the XML names that encoding/xml uses for the definition of the type and the definitions it refers to:
+// the type name for the root element and the field name for properties without xml tag whose JSON name differs.
+func (b definitionBuilder) addXMLNames(t reflect.Type, visited map[string]bool) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || isAnyType(t) {
+		return
+	}
+	name := keyFrom(t, b.Config)
+	def, ok := b.Definitions[name]
+	if !ok || visited[name] {
+		return
+	}
+	visited[name] = true
+	if def.XML == nil && t.Name() != "" {
+		def.XML = &spec.XMLObject{Name: t.Name()}
+	}
+	b.addXMLPropertyNames(&def, t, visited)
+	b.Definitions[name] = def
+}
+
+func (b definitionBuilder) addXMLPropertyNames(def *spec.Schema, st reflect.Type, visited map[string]bool) {
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if !field.IsExported() && !isEmbeddedStruct(field) || field.Name == "XMLName" {
+			continue
+		}
+		if isEmbeddedStruct(field) && !hasNamedJSONTag(field) {
+			// its properties are part of the definition
+			b.addXMLPropertyNames(def, field.Type, visited)
+			continue
+		}
+		b.addXMLNames(field.Type, visited)
+		jsonName := b.jsonNameOfField(field)
+		prop, ok := def.Properties[jsonName]
+		if _, tagged := field.Tag.Lookup("xml"); !ok || tagged || jsonName == field.Name {
+			continue
+		}
+		prop.XML = xmlObject(prop.XML)
+		prop.XML.Name = field.Name
+		def.Properties[jsonName] = prop
+	}
+}
+
+// sortDefinitionProperties makes sure the properties of all definitions are serialized in alphabetical order.
+// The spec package orders properties by name unless they have a x-order extension, so these are removed.
+func sortDefinitionProperties(definitions spec.Definitions) {
+	for name, def := range definitions {
+		sortSchemaProperties(&def)
+		definitions[name] = def
+	}
+}
+
+func sortSchemaProperties(s *spec.Schema) {
+	sort.Strings(s.Required)
+	for name, prop := range s.Properties {
+		delete(prop.Extensions, "x-order")
+		sortSchemaProperties(&prop)
+		s.Properties[name] = prop
+	}
+	if s.Items != nil && s.Items.Schema != nil {
+		sortSchemaProperties(s.Items.Schema)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		sortSchemaProperties(s.AdditionalProperties.Schema)
+	}
+}
+
+// addMSEnumExtensions adds a x-ms-enum extension, as used by AutoRest, to each enum schema of the definitions
+// that does not have one. Definitions are named after themselves and inline enums after their property.
+func addMSEnumExtensions(definitions spec.Definitions) {
+	for name, def := range definitions {
+		addMSEnumExtension(&def, name)
+		definitions[name] = def
+	}
+}
+
+func addMSEnumExtension(s *spec.Schema, name string) {
+	if len(s.Enum) > 0 {
+		if _, ok := s.Extensions["x-ms-enum"]; !ok {
+			s.AddExtension("x-ms-enum", map[string]interface{}{"name": name, "modelAsString": false})
+		}
+	}
+	for prop, each := range s.Properties {
+		addMSEnumExtension(&each, prop)
+		s.Properties[prop] = each
+	}
+	if s.Items != nil && s.Items.Schema != nil {
+		addMSEnumExtension(s.Items.Schema, name)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		addMSEnumExtension(s.AdditionalProperties.Schema, name)
+	}
 }
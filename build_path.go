@@ -17,30 +17,99 @@ const (
 	// KeyOpenAPITags is a Metadata key for a restful Route
 	KeyOpenAPITags    = "openapi.tags"
 	KeyOpenAPIDisable = "openapi.disable"
+	// KeyOpenAPIResponseHeaders is a Metadata key for a restful Route; its value must be a []ResponseHeader
+	KeyOpenAPIResponseHeaders = "openapi.responseHeaders"
+	// KeyOpenAPIResponseCode is a Metadata key for a restful Route; its value (int or string) is the
+	// status code of the success response, which otherwise is 201 for POST and 200 for all other methods
+	KeyOpenAPIResponseCode = "openapi.responseCode"
+	// KeyOpenAPIResponses is a Metadata key for a restful Route; its value must be a map[int]interface{}
+	// of status code to response model. Responses registered with Returns take precedence.
+	KeyOpenAPIResponses = "openapi.responses"
+	// KeyOpenAPIInternal is a Metadata key for a restful Route; if true then its operation
+	// gets the x-internal extension or is left out entirely if Config.StripInternalOperations is set
+	KeyOpenAPIInternal = "x-internal"
+	// KeyOpenAPIRequestBodyRequired is a Metadata key for a restful Route; its bool value
+	// overrides Config.RequestBodyRequiredByDefault for the body parameter of the route
+	KeyOpenAPIRequestBodyRequired = "openapi.requestBodyRequired"
 
 	// ExtensionPrefix is the only prefix accepted for VendorExtensible extension keys
 	ExtensionPrefix = "x-"
 
 	arrayType      = "array"
 	definitionRoot = "#/definitions/"
+
+	// pathDescriptionExtension holds the documentation of the WebServices of a path item.
+	// Swagger 2.0 path items have no description field.
+	pathDescriptionExtension = "x-description"
+	// goHandlerExtension holds the name of the Go function of an operation, see Config.EmitHandlerExtension.
+	goHandlerExtension = "x-go-handler"
+	// requestValidationExtension tells proxies to validate requests, see Config.RequestValidation.
+	requestValidationExtension = "x-request-validation"
+	// responseValidationExtension tells contract testing tools to validate responses, see Config.ResponseValidation.
+	responseValidationExtension = "x-response-validation"
+	// rateLimitExtension holds the RateLimit of an operation, see Config.RateLimitFunc.
+	rateLimitExtension = "x-rate-limit"
 )
 
 func buildPaths(ws *restful.WebService, cfg Config) spec.Paths {
 	p := spec.Paths{Paths: map[string]spec.PathItem{}}
 	for _, each := range ws.Routes() {
-		if each.Metadata[KeyOpenAPIDisable] != nil && each.Metadata[KeyOpenAPIDisable].(bool) {
+		if isExcludedRoute(each, cfg) {
 			continue
 		}
 		path, patterns := sanitizePath(each.Path)
 		existingPathItem, ok := p.Paths[path]
 		if !ok {
 			existingPathItem = spec.PathItem{}
+			addPathItemDescription(&existingPathItem, ws.Documentation())
 		}
 		p.Paths[path] = buildPathItem(ws, each, existingPathItem, patterns, cfg)
 	}
 	return p
 }
 
+// addPathItemDescription appends the documentation to the description of the path item.
+func addPathItemDescription(item *spec.PathItem, doc string) {
+	if doc == "" {
+		return
+	}
+	if existing, ok := item.Extensions.GetString(pathDescriptionExtension); ok && existing != "" {
+		for _, each := range strings.Split(existing, "\n") {
+			if each == doc {
+				return
+			}
+		}
+		doc = existing + "\n" + doc
+	}
+	item.AddExtension(pathDescriptionExtension, doc)
+}
+
+// isExcludedRoute returns true if the route must not be part of the generated document.
+func isExcludedRoute(r restful.Route, cfg Config) bool {
+	if disabled, ok := r.Metadata[KeyOpenAPIDisable].(bool); ok && disabled {
+		return true
+	}
+	if isSpecRoute(r, cfg) {
+		return true
+	}
+	return cfg.StripInternalOperations && isInternalRoute(r)
+}
+
+// isSpecRoute returns true if the route is on or below the SpecPath of the config.
+func isSpecRoute(r restful.Route, cfg Config) bool {
+	specPath := strings.TrimSuffix(cfg.SpecPath, "/")
+	if specPath == "" {
+		return false
+	}
+	path := strings.TrimSuffix(r.Path, "/")
+	return path == specPath || strings.HasPrefix(path, specPath+"/")
+}
+
+func isInternalRoute(r restful.Route) bool {
+	internal, ok := r.Metadata[KeyOpenAPIInternal].(bool)
+	return ok && internal
+}
+
 // sanitizePath removes regex expressions from named path params,
 // since openapi only supports setting the pattern as a property named "pattern".
 // Expressions like "/api/v1/{name:[a-z]}/" are converted to "/api/v1/{name}/".
@@ -70,27 +139,104 @@ func sanitizePath(restfulPath string) (string, map[string]string) {
 
 func buildPathItem(ws *restful.WebService, r restful.Route, existingPathItem spec.PathItem, patterns map[string]string, cfg Config) spec.PathItem {
 	op := buildOperation(ws, r, patterns, cfg)
-	switch r.Method {
+	setPathItemOperation(&existingPathItem, r.Method, op)
+	return existingPathItem
+}
+
+// setPathItemOperation sets the operation of the path item for the HTTP method.
+func setPathItemOperation(item *spec.PathItem, method string, op *spec.Operation) {
+	switch method {
 	case http.MethodGet:
-		existingPathItem.Get = op
+		item.Get = op
 	case http.MethodPost:
-		existingPathItem.Post = op
+		item.Post = op
 	case http.MethodPut:
-		existingPathItem.Put = op
+		item.Put = op
 	case http.MethodDelete:
-		existingPathItem.Delete = op
+		item.Delete = op
 	case http.MethodPatch:
-		existingPathItem.Patch = op
+		item.Patch = op
 	case http.MethodOptions:
-		existingPathItem.Options = op
+		item.Options = op
 	case http.MethodHead:
-		existingPathItem.Head = op
+		item.Head = op
 	}
-	return existingPathItem
+}
+
+// pathItemOperations returns all operations of the path item.
+func pathItemOperations(item *spec.PathItem) []*spec.Operation {
+	ops := []*spec.Operation{}
+	for _, each := range []*spec.Operation{item.Get, item.Put, item.Post, item.Delete, item.Options, item.Head, item.Patch} {
+		if each != nil {
+			ops = append(ops, each)
+		}
+	}
+	return ops
+}
+
+// pathItemOperationsByMethod returns all operations of the path item by their HTTP method.
+func pathItemOperationsByMethod(item spec.PathItem) map[string]*spec.Operation {
+	ops := map[string]*spec.Operation{}
+	for method, each := range map[string]*spec.Operation{
+		http.MethodGet:     item.Get,
+		http.MethodPut:     item.Put,
+		http.MethodPost:    item.Post,
+		http.MethodDelete:  item.Delete,
+		http.MethodOptions: item.Options,
+		http.MethodHead:    item.Head,
+		http.MethodPatch:   item.Patch,
+	} {
+		if each != nil {
+			ops[method] = each
+		}
+	}
+	return ops
+}
+
+// shareCommonPathParameters moves the path parameters that are identical for all operations
+// of a path item to the parameters of that path item.
+func shareCommonPathParameters(paths *spec.Paths) {
+	for path, item := range paths.Paths {
+		ops := pathItemOperations(&item)
+		if len(ops) < 2 {
+			continue
+		}
+		candidates := append([]spec.Parameter{}, ops[0].Parameters...)
+		for _, param := range candidates {
+			if param.In != "path" {
+				continue
+			}
+			shared := true
+			for _, other := range ops[1:] {
+				if indexOfParameter(other.Parameters, param) == -1 {
+					shared = false
+					break
+				}
+			}
+			if !shared {
+				continue
+			}
+			for _, each := range ops {
+				i := indexOfParameter(each.Parameters, param)
+				each.Parameters = append(each.Parameters[:i], each.Parameters[i+1:]...)
+			}
+			item.Parameters = append(item.Parameters, param)
+		}
+		paths.Paths[path] = item
+	}
+}
+
+func indexOfParameter(params []spec.Parameter, param spec.Parameter) int {
+	for i, each := range params {
+		if reflect.DeepEqual(each, param) {
+			return i
+		}
+	}
+	return -1
 }
 
 func buildOperation(ws *restful.WebService, r restful.Route, patterns map[string]string, cfg Config) *spec.Operation {
-	o := spec.NewOperation(r.Operation)
+	o := spec.NewOperation(operationID(r, cfg))
 	o.Description = r.Notes
 	o.Summary = stripTags(r.Doc)
 	o.Consumes = r.Consumes
@@ -104,6 +250,10 @@ func buildOperation(ws *restful.WebService, r restful.Route, patterns map[string
 		}
 	}
 
+	if isInternalRoute(r) {
+		o.AddExtension(KeyOpenAPIInternal, true)
+	}
+
 	extractVendorExtensions(&o.VendorExtensible, r.ExtensionProperties)
 
 	// collect any path parameters
@@ -112,7 +262,15 @@ func buildOperation(ws *restful.WebService, r restful.Route, patterns map[string
 		o.Parameters = append(o.Parameters, p)
 	}
 	// route specific params
+	formData := isFormDataSample(r.ReadSample)
 	for _, param := range r.ParameterDocs {
+		if formData && param.Kind() == restful.BodyParameterKind {
+			// the fields of the sample are the parameters
+			formParams := buildFormDataParameters(reflect.TypeOf(r.ReadSample), cfg)
+			o.Parameters = append(o.Parameters, formParams...)
+			o.Consumes = formDataConsumes(o.Consumes, formParams)
+			continue
+		}
 		p := buildParameter(r, param, patterns[param.Data().Name], cfg)
 		if p.Name == "body" && p.In == "body" && r.ReadSample != nil {
 			p.Example = r.ReadSample
@@ -126,25 +284,156 @@ func buildOperation(ws *restful.WebService, r restful.Route, patterns map[string
 		rsp := buildResponse(r.Produces, v, cfg)
 		props.StatusCodeResponses[k] = rsp
 	}
+	for code, model := range responseModels(r) {
+		if _, ok := props.StatusCodeResponses[code]; ok {
+			continue
+		}
+		e := restful.ResponseError{Code: code, Message: http.StatusText(code), Model: model}
+		props.StatusCodeResponses[code] = buildResponse(r.Produces, e, cfg)
+	}
 	if r.DefaultResponse != nil {
 		rsp := buildResponse(r.Produces, *r.DefaultResponse, cfg)
 		o.Responses.Default = &rsp
+	} else if cfg.DefaultResponseFunc != nil {
+		o.Responses.Default = cfg.DefaultResponseFunc(r)
 	}
-	if _, ok := props.StatusCodeResponses[200]; !ok && r.WriteSample != nil {
-		rsp := spec.Response{ResponseProps: spec.ResponseProps{Description: http.StatusText(http.StatusOK)}}
+	successCode := successStatusCode(r)
+	if !hasSuccessResponse(props.StatusCodeResponses) && r.WriteSample != nil {
+		rsp := spec.Response{ResponseProps: spec.ResponseProps{Description: http.StatusText(successCode)}}
 		for _, produce := range r.Produces {
 			if produce == "application/json" {
 				rsp.AddExample("application/json", r.WriteSample)
 			}
 		}
-		o.Responses.StatusCodeResponses[200] = rsp
+		if producesBinary(r.Produces) {
+			rsp.Schema = binarySchema()
+		}
+		o.Responses.StatusCodeResponses[successCode] = rsp
 	}
 	if len(o.Responses.StatusCodeResponses) == 0 {
-		o.Responses.StatusCodeResponses[200] = spec.Response{ResponseProps: spec.ResponseProps{Description: http.StatusText(http.StatusOK)}}
+		// the handler writes its response without a documented type, so anything can be returned
+		rsp := spec.Response{ResponseProps: spec.ResponseProps{Description: http.StatusText(successCode), Schema: &spec.Schema{}}}
+		if producesBinary(r.Produces) {
+			rsp.Schema = binarySchema()
+		}
+		o.Responses.StatusCodeResponses[successCode] = rsp
+	}
+	if cfg.ErrorType != nil {
+		addErrorResponses(o.Responses, cfg)
+	}
+	if headers, ok := r.Metadata[KeyOpenAPIResponseHeaders].([]ResponseHeader); ok {
+		addResponseHeaders(o.Responses, headers)
+	}
+	if cfg.LinkBuilderFunc != nil {
+		addResponseLinks(o.Responses, cfg.LinkBuilderFunc(r))
+	}
+	if cfg.EmitHandlerExtension {
+		if name := handlerFullName(r.Function); name != "" {
+			o.AddExtension(goHandlerExtension, name)
+		}
+	}
+	if cfg.RequestValidation && !isRequestValidationExcluded(r, cfg) {
+		o.AddExtension(requestValidationExtension, map[string]interface{}{
+			"validateBody":    true,
+			"validateQuery":   true,
+			"validateHeaders": true,
+		})
+	}
+	if cfg.ResponseValidation {
+		o.AddExtension(responseValidationExtension, map[string]interface{}{"validate": true})
+	}
+	if cfg.RateLimitFunc != nil {
+		if limit := cfg.RateLimitFunc(r); limit != nil {
+			o.AddExtension(rateLimitExtension, *limit)
+		}
+	}
+	if cfg.AWSAPIGatewayConfig != nil {
+		path, _ := sanitizePath(r.Path)
+		addOperationExtensions(o, cfg.AWSAPIGatewayConfig.extensions(path, r))
+	}
+	if cfg.PathExtensionsFunc != nil {
+		path, _ := sanitizePath(r.Path)
+		addOperationExtensions(o, cfg.PathExtensionsFunc(path, r.Method))
+	}
+	if cfg.OperationPostProcessor != nil {
+		cfg.OperationPostProcessor(o, r)
 	}
 	return o
 }
 
+// isRequestValidationExcluded returns true if the path of the route, as documented or as routed,
+// is one of the Config.RequestValidationExclusions.
+func isRequestValidationExcluded(r restful.Route, cfg Config) bool {
+	path, _ := sanitizePath(r.Path)
+	return containsString(cfg.RequestValidationExclusions, path) || containsString(cfg.RequestValidationExclusions, r.Path)
+}
+
+// addErrorResponses sets the schema of the Config.ErrorType on all error responses without a schema.
+func addErrorResponses(responses *spec.Responses, cfg Config) {
+	errorSchema := func() *spec.Schema {
+		return buildResponse(nil, restful.ResponseError{Model: cfg.ErrorType}, cfg).Schema
+	}
+	if len(cfg.ErrorStatusCodes) > 0 {
+		for _, code := range cfg.ErrorStatusCodes {
+			rsp, ok := responses.StatusCodeResponses[code]
+			if !ok {
+				rsp.Description = http.StatusText(code)
+			}
+			if rsp.Schema == nil {
+				rsp.Schema = errorSchema()
+			}
+			responses.StatusCodeResponses[code] = rsp
+		}
+		return
+	}
+	for code, rsp := range responses.StatusCodeResponses {
+		if code >= 200 && code <= 299 || rsp.Schema != nil {
+			continue
+		}
+		rsp.Schema = errorSchema()
+		responses.StatusCodeResponses[code] = rsp
+	}
+	if responses.Default != nil && responses.Default.Schema == nil {
+		responses.Default.Schema = errorSchema()
+	}
+}
+
+// responseModels returns the status code to model mapping from the route Metadata, if any.
+func responseModels(r restful.Route) map[int]interface{} {
+	models, _ := r.Metadata[KeyOpenAPIResponses].(map[int]interface{})
+	return models
+}
+
+// successStatusCode returns the status code of the success response of a route.
+func successStatusCode(r restful.Route) int {
+	if value, ok := r.Metadata[KeyOpenAPIResponseCode]; ok {
+		code := 0
+		switch v := value.(type) {
+		case int:
+			code = v
+		case string:
+			code, _ = strconv.Atoi(v)
+		}
+		if code >= 100 && code <= 599 {
+			return code
+		}
+	}
+	if r.Method == http.MethodPost {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+// hasSuccessResponse returns true if any of the responses has a 2xx status code.
+func hasSuccessResponse(responses map[int]spec.Response) bool {
+	for code := range responses {
+		if code >= 200 && code <= 299 {
+			return true
+		}
+	}
+	return false
+}
+
 // stringAutoType automatically picks the correct type from an ambiguously typed
 // string. Ex. numbers become int, true/false become bool, etc.
 func stringAutoType(ambiguous string) interface{} {
@@ -170,6 +459,16 @@ func extractVendorExtensions(extensible *spec.VendorExtensible, extensions restf
 	}
 }
 
+// addOperationExtensions adds the extensions to the operation, keeping the case of their keys.
+// Like the ExtensionProperties of a route, keys that do not start with ExtensionPrefix are ignored.
+func addOperationExtensions(o *spec.Operation, extensions map[string]interface{}) {
+	for key, value := range extensions {
+		if strings.HasPrefix(strings.ToLower(key), ExtensionPrefix) {
+			setExtension(&o.VendorExtensible, key, value)
+		}
+	}
+}
+
 func buildParameter(r restful.Route, restfulParam *restful.Parameter, pattern string, cfg Config) spec.Parameter {
 	p := spec.Parameter{}
 	param := restfulParam.Data()
@@ -229,6 +528,9 @@ func buildParameter(r restful.Route, restfulParam *restful.Parameter, pattern st
 	p.Description = param.Description
 	p.Name = param.Name
 	p.Required = param.Required
+	if param.Kind == restful.BodyParameterKind {
+		p.Required = isRequestBodyRequired(r, param, cfg)
+	}
 	p.AllowEmptyValue = param.AllowEmptyValue
 
 	if param.Kind == restful.PathParameterKind {
@@ -250,14 +552,18 @@ func buildParameter(r restful.Route, restfulParam *restful.Parameter, pattern st
 			if isPrimitive {
 				mapped := jsonSchemaType(dataTypeName)
 				p.Schema.Items.Schema.Type = []string{mapped}
+			} else if named, ok := namedPrimitiveSchema(st.Elem(), cfg); ok {
+				p.Schema.Items.Schema = named
 			} else {
 				p.Schema.Items.Schema.Ref = spec.MustCreateRef(definitionRoot + dataTypeName)
 			}
+		} else if named, ok := namedPrimitiveSchema(st, cfg); ok {
+			p.Schema = named
 		} else {
 			dataTypeName := keyFrom(st, cfg)
 			p.Schema.Ref = spec.MustCreateRef(definitionRoot + dataTypeName)
 		}
-
+		p.Schema.Example = exampleOf(st, cfg)
 	} else {
 		if param.AllowMultiple {
 			p.Type = arrayType
@@ -276,9 +582,23 @@ func buildParameter(r restful.Route, restfulParam *restful.Parameter, pattern st
 	return p
 }
 
+// isRequestBodyRequired returns whether the body parameter is required; the route Metadata takes precedence over the Config.
+func isRequestBodyRequired(r restful.Route, param restful.ParameterData, cfg Config) bool {
+	if required, ok := r.Metadata[KeyOpenAPIRequestBodyRequired].(bool); ok {
+		return required
+	}
+	if cfg.RequestBodyRequiredByDefault != nil && !*cfg.RequestBodyRequiredByDefault {
+		return false
+	}
+	return param.Required
+}
+
 func buildResponse(produces []string, e restful.ResponseError, cfg Config) (r spec.Response) {
 	r.Description = e.Message
-	if e.Model != nil {
+	if e.Model != nil && producesBinary(produces) {
+		// the model describes the content, not the encoding of the payload
+		r.Schema = binarySchema()
+	} else if e.Model != nil {
 		for _, produce := range produces {
 			if produce == "application/json" {
 				r.AddExample("application/json", e.Model)
@@ -291,7 +611,9 @@ func buildResponse(produces []string, e restful.ResponseError, cfg Config) (r sp
 			st = st.Elem()
 		}
 		r.Schema = new(spec.Schema)
-		if st.Kind() == reflect.Array || st.Kind() == reflect.Slice {
+		if isAnyType(st) {
+			// no definition can be built for this model, leave the schema empty (any type)
+		} else if st.Kind() == reflect.Array || st.Kind() == reflect.Slice {
 			modelName := keyFrom(st.Elem(), cfg)
 			r.Schema.Type = []string{arrayType}
 			r.Schema.Items = &spec.SchemaOrArray{
@@ -301,9 +623,13 @@ func buildResponse(produces []string, e restful.ResponseError, cfg Config) (r sp
 			if isPrimitive {
 				mapped := jsonSchemaType(modelName)
 				r.Schema.Items.Schema.Type = []string{mapped}
+			} else if named, ok := namedPrimitiveSchema(st.Elem(), cfg); ok {
+				r.Schema.Items.Schema = named
 			} else {
 				r.Schema.Items.Schema.Ref = spec.MustCreateRef(definitionRoot + modelName)
 			}
+		} else if named, ok := namedPrimitiveSchema(st, cfg); ok {
+			r.Schema = named
 		} else {
 			modelName := keyFrom(st, cfg)
 			if isPrimitiveType(modelName) {
@@ -315,6 +641,10 @@ func buildResponse(produces []string, e restful.ResponseError, cfg Config) (r sp
 				r.Schema.Ref = spec.MustCreateRef(definitionRoot + modelName)
 			}
 		}
+		r.Schema.Example = exampleOf(st, cfg)
+		if producesXML(produces) {
+			setXMLResponseName(r.Schema, st)
+		}
 	}
 
 	if len(e.Headers) > 0 {
@@ -328,6 +658,136 @@ func buildResponse(produces []string, e restful.ResponseError, cfg Config) (r sp
 	return r
 }
 
+// exampleOf returns the example value of the type if Config.EmitExamples is set.
+func exampleOf(t reflect.Type, cfg Config) interface{} {
+	if !cfg.EmitExamples || cfg.ExampleFunc == nil {
+		return nil
+	}
+	return cfg.ExampleFunc(t)
+}
+
+// Link describes a relation from a response to another operation, like the link object of OpenAPI 3.
+// Either OperationRef or OperationID identifies the target operation.
+type Link struct {
+	OperationRef string `json:"operationRef,omitempty"`
+	OperationID  string `json:"operationId,omitempty"`
+	// Parameters of the target operation by name, with values as constants or runtime expressions e.g. $response.body#/id
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	// RequestBody of the target operation, as a constant or runtime expression
+	RequestBody interface{} `json:"requestBody,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// addResponseLinks adds the links as the x-links extension to all success responses.
+// Swagger 2.0 has no links so the extension carries the link objects of OpenAPI 3.
+func addResponseLinks(responses *spec.Responses, links map[string]Link) {
+	if len(links) == 0 {
+		return
+	}
+	for code, rsp := range responses.StatusCodeResponses {
+		if code >= http.StatusOK && code < http.StatusMultipleChoices {
+			rsp.AddExtension("x-links", links)
+			responses.StatusCodeResponses[code] = rsp
+		}
+	}
+}
+
+// RateLimit is the rate limit of an operation for API gateways, emitted as the x-rate-limit extension.
+// Zero values are left out.
+type RateLimit struct {
+	RequestsPerSecond int `json:"requestsPerSecond,omitempty"`
+	RequestsPerMinute int `json:"requestsPerMinute,omitempty"`
+	BurstSize         int `json:"burstSize,omitempty"`
+}
+
+// ResponseHeader describes a header that is sent with every response of a Route.
+// Register a list of them using the KeyOpenAPIResponseHeaders Metadata key, e.g.
+//
+//	Metadata(KeyOpenAPIResponseHeaders, []ResponseHeader{{Name: "X-Request-ID", Type: "string"}})
+type ResponseHeader struct {
+	Name        string
+	Description string
+	// Type defaults to "string" if empty
+	Type   string
+	Format string
+}
+
+// addResponseHeaders adds the headers to all responses that do not already define them.
+func addResponseHeaders(responses *spec.Responses, headers []ResponseHeader) {
+	for code, rsp := range responses.StatusCodeResponses {
+		responses.StatusCodeResponses[code] = withResponseHeaders(rsp, headers)
+	}
+	if responses.Default != nil {
+		rsp := withResponseHeaders(*responses.Default, headers)
+		responses.Default = &rsp
+	}
+}
+
+func withResponseHeaders(rsp spec.Response, headers []ResponseHeader) spec.Response {
+	for _, each := range headers {
+		if _, ok := rsp.Headers[each.Name]; ok {
+			// headers declared with the response itself take precedence
+			continue
+		}
+		if rsp.Headers == nil {
+			rsp.Headers = make(map[string]spec.Header, len(headers))
+		}
+		h := spec.Header{}
+		h.Type = each.Type
+		if h.Type == "" {
+			h.Type = "string"
+		}
+		h.Format = each.Format
+		h.Description = each.Description
+		rsp.Headers[each.Name] = h
+	}
+	return rsp
+}
+
+// producesBinary returns true if the only payload a route produces is application/octet-stream.
+func producesBinary(produces []string) bool {
+	binary := false
+	for _, each := range produces {
+		switch each {
+		case restful.MIME_OCTET:
+			binary = true
+		case restful.MIME_JSON:
+			return false
+		}
+	}
+	return binary
+}
+
+// producesXML returns true if a route produces application/xml or text/xml.
+func producesXML(produces []string) bool {
+	for _, each := range produces {
+		if each == restful.MIME_XML || each == "text/xml" {
+			return true
+		}
+	}
+	return false
+}
+
+// setXMLResponseName sets the XML name of a response schema that is not a reference to the name of its Go type,
+// which encoding/xml uses for the element of the value or of each item of a slice.
+// Definitions get their XML names from addXMLNames.
+func setXMLResponseName(s *spec.Schema, t reflect.Type) {
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		if s.Items != nil && s.Items.Schema != nil {
+			setXMLResponseName(s.Items.Schema, t.Elem())
+		}
+		return
+	}
+	if ref := s.Ref; ref.String() != "" || t.Name() == "" || s.XML != nil {
+		return
+	}
+	s.XML = &spec.XMLObject{Name: t.Name()}
+}
+
+func binarySchema() *spec.Schema {
+	return &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"string"}, Format: "binary"}}
+}
+
 // buildHeader builds a specification header structure from restful.Header
 func buildHeader(header restful.Header) spec.Header {
 	responseHeader := spec.Header{}
@@ -373,6 +833,21 @@ func isPrimitiveType(modelName string) bool {
 	return strings.Contains("uint uint8 uint16 uint32 uint64 int int8 int16 int32 int64 float32 float64 bool string byte rune time.Time time.Duration", modelName)
 }
 
+// namedPrimitiveSchema returns the schema of a named type of a primitive kind, such as type Status string,
+// for which no definition is built.
+func namedPrimitiveSchema(t reflect.Type, cfg Config) (*spec.Schema, bool) {
+	b := definitionBuilder{Config: cfg}
+	modelName := keyFrom(t, cfg)
+	if t.PkgPath() == "" || t.Kind() == reflect.Struct || !b.isPrimitiveType(modelName, t.Kind()) {
+		return nil, false
+	}
+	return &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:   []string{b.jsonSchemaType(modelName, t.Kind())},
+		Format: b.jsonSchemaFormat(modelName, t.Kind()),
+		Enum:   enumValuesOf(t, cfg),
+	}}, true
+}
+
 func jsonSchemaType(modelName string) string {
 	schemaMap := map[string]string{
 		"uint":   "integer",
@@ -1,6 +1,9 @@
 package restfulspec
 
 import (
+	"encoding/json"
+	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/go-openapi/spec"
@@ -352,3 +355,614 @@ func TestWritesPrimitive(t *testing.T) {
 		}
 	}
 }
+
+func TestResponseHeadersFromMetadata(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/headers")
+	ws.Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("").To(dummy).
+		Metadata(KeyOpenAPIResponseHeaders, []ResponseHeader{
+			{Name: "X-Request-ID", Description: "request identifier"},
+			{Name: "X-Rate-Limit-Remaining", Type: "integer", Format: "int32"},
+		}).
+		ReturnsWithHeaders(200, "ok", Sample{}, map[string]restful.Header{
+			"X-Request-ID": {Items: &restful.Items{Type: "integer"}},
+		}).
+		Returns(404, "not found", nil))
+
+	p := buildPaths(ws, Config{})
+	t.Log(asJSON(p))
+
+	responses := p.Paths["/tests/headers"].Get.Responses.StatusCodeResponses
+	notFound := responses[404]
+	if got, want := notFound.Headers["X-Request-ID"].Type, "string"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := notFound.Headers["X-Request-ID"].Description, "request identifier"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := notFound.Headers["X-Rate-Limit-Remaining"].Format, "int32"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	ok := responses[200]
+	if got, want := ok.Headers["X-Request-ID"].Description, ""; got != want {
+		t.Errorf("response header should not be overwritten, got %v want %v", got, want)
+	}
+	if got, want := ok.Headers["X-Rate-Limit-Remaining"].Type, "integer"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestDefaultResponseFunc(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/default")
+	ws.Route(ws.GET("/a").To(dummy).
+		Returns(200, "ok", Sample{}))
+	ws.Route(ws.GET("/b").To(dummy).
+		Returns(200, "ok", Sample{}).
+		DefaultReturns("route default", Sample{}))
+
+	cfg := Config{
+		DefaultResponseFunc: func(route restful.Route) *spec.Response {
+			return spec.NewResponse().WithDescription("error for " + route.Path)
+		},
+	}
+	p := buildPaths(ws, cfg)
+	t.Log(asJSON(p))
+
+	if got, want := p.Paths["/tests/default/a"].Get.Responses.Default.Description, "error for /tests/default/a"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := p.Paths["/tests/default/b"].Get.Responses.Default.Description, "route default"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestSuccessResponseCode(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/codes")
+	ws.Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("").To(dummy).Writes(Sample{}))
+	ws.Route(ws.POST("").To(dummy).Reads(Sample{}).Writes(Sample{}))
+	ws.Route(ws.PUT("").To(dummy).Metadata(KeyOpenAPIResponseCode, "202").Writes(Sample{}))
+	ws.Route(ws.DELETE("").To(dummy).Metadata(KeyOpenAPIResponseCode, 204))
+	ws.Route(ws.PATCH("").To(dummy).Returns(200, "patched", Sample{}).Writes(Sample{}))
+
+	p := buildPaths(ws, Config{})
+	t.Log(asJSON(p))
+
+	item := p.Paths["/tests/codes"]
+	for method, tc := range map[string]struct {
+		op       *spec.Operation
+		expected int
+	}{
+		"GET":    {item.Get, 200},
+		"POST":   {item.Post, 201},
+		"PUT":    {item.Put, 202},
+		"DELETE": {item.Delete, 204},
+		"PATCH":  {item.Patch, 200},
+	} {
+		if got, want := len(tc.op.Responses.StatusCodeResponses), 1; got != want {
+			t.Errorf("%s: got %v responses want %v", method, got, want)
+		}
+		if _, ok := tc.op.Responses.StatusCodeResponses[tc.expected]; !ok {
+			t.Errorf("%s: expected response with status code %d", method, tc.expected)
+		}
+	}
+}
+
+type ErrorBody struct {
+	Message string `json:"message"`
+}
+
+func TestResponsesFromMetadata(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/responses")
+	ws.Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("").To(dummy).
+		Metadata(KeyOpenAPIResponses, map[int]interface{}{
+			200: Sample{},
+			400: ErrorBody{},
+			500: ErrorBody{},
+		}).
+		Returns(500, "internal server error", nil))
+
+	p := buildPaths(ws, Config{})
+	t.Log(asJSON(p))
+
+	responses := p.Paths["/tests/responses"].Get.Responses.StatusCodeResponses
+	if got, want := len(responses), 3; got != want {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	if got, want := responses[200].Schema.Ref.String(), "#/definitions/restfulspec.Sample"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := responses[400].Schema.Ref.String(), "#/definitions/restfulspec.ErrorBody"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := responses[400].Description, "Bad Request"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if responses[500].Schema != nil {
+		t.Errorf("response registered with Returns should take precedence")
+	}
+
+	d := buildDefinitions(ws, Config{})
+	if _, ok := d["restfulspec.ErrorBody"]; !ok {
+		t.Errorf("missing definition for response model")
+	}
+}
+
+func TestErrorType(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/errors")
+	ws.Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("").To(dummy).
+		Returns(200, "ok", Sample{}).
+		Returns(404, "not found", nil).
+		Returns(409, "conflict", Item{}).
+		DefaultReturns("unexpected", nil))
+
+	p := buildPaths(ws, Config{ErrorType: ErrorBody{}})
+	t.Log(asJSON(p))
+
+	errorRef := "#/definitions/restfulspec.ErrorBody"
+	responses := p.Paths["/tests/errors"].Get.Responses
+	if got, want := responses.StatusCodeResponses[404].Schema.Ref.String(), errorRef; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := responses.StatusCodeResponses[409].Schema.Ref.String(), "#/definitions/restfulspec.Item"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := responses.Default.Schema.Ref.String(), errorRef; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := responses.StatusCodeResponses[200].Schema.Ref.String(), "#/definitions/restfulspec.Sample"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	p = buildPaths(ws, Config{ErrorType: ErrorBody{}, ErrorStatusCodes: []int{400, 404}})
+	responses = p.Paths["/tests/errors"].Get.Responses
+	if got, want := responses.StatusCodeResponses[400].Description, "Bad Request"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := responses.StatusCodeResponses[400].Schema.Ref.String(), errorRef; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := responses.StatusCodeResponses[404].Description, "not found"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if responses.Default.Schema != nil {
+		t.Errorf("default response should not use the error type when status codes are configured")
+	}
+
+	d := buildDefinitions(ws, Config{ErrorType: ErrorBody{}})
+	if _, ok := d["restfulspec.ErrorBody"]; !ok {
+		t.Errorf("missing definition for error type")
+	}
+}
+
+func TestProducesOctetStream(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/files")
+	ws.Route(ws.GET("/{name}").To(dummy).
+		Produces(restful.MIME_OCTET).
+		Param(ws.PathParameter("name", "file name")).
+		Returns(200, "file content", Item{}).
+		Writes(Item{}))
+	ws.Route(ws.GET("").To(dummy).
+		Produces(restful.MIME_OCTET))
+
+	p := buildPaths(ws, Config{})
+	t.Log(asJSON(p))
+
+	for _, path := range []string{"/tests/files/{name}", "/tests/files"} {
+		rsp := p.Paths[path].Get.Responses.StatusCodeResponses[200]
+		if rsp.Schema == nil {
+			t.Fatalf("%s: missing schema", path)
+		}
+		if got, want := rsp.Schema.Type[0], "string"; got != want {
+			t.Errorf("%s: got %v want %v", path, got, want)
+		}
+		if got, want := rsp.Schema.Format, "binary"; got != want {
+			t.Errorf("%s: got %v want %v", path, got, want)
+		}
+	}
+
+	if got, want := len(buildDefinitions(ws, Config{})), 0; got != want {
+		t.Errorf("got %v definitions want %v", got, want)
+	}
+}
+
+func TestRouteWithoutResponseType(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/raw")
+	ws.Route(ws.GET("").To(func(req *restful.Request, resp *restful.Response) {
+		resp.ResponseWriter.Write([]byte("raw"))
+	}))
+	ws.Route(ws.GET("/writer").To(dummy).
+		Returns(200, "ok", (*http.ResponseWriter)(nil)))
+
+	p := buildPaths(ws, Config{})
+	t.Log(asJSON(p))
+
+	for _, path := range []string{"/tests/raw", "/tests/raw/writer"} {
+		rsp := p.Paths[path].Get.Responses.StatusCodeResponses[200]
+		if rsp.Schema == nil {
+			t.Fatalf("%s: missing schema", path)
+		}
+		if got, want := asJSON(rsp.Schema), "{}"; got != want {
+			t.Errorf("%s: got %v want %v", path, got, want)
+		}
+	}
+	if got, want := len(buildDefinitions(ws, Config{})), 0; got != want {
+		t.Errorf("got %v definitions want %v", got, want)
+	}
+}
+
+func TestOperationPostProcessor(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/processed")
+	ws.Route(ws.GET("").To(dummy).Operation("getProcessed").Writes(Sample{}))
+	ws.Route(ws.DELETE("").To(dummy).Operation("deleteProcessed"))
+
+	cfg := Config{
+		OperationPostProcessor: func(op *spec.Operation, route restful.Route) {
+			op.AddExtension("x-method", route.Method)
+			op.Parameters = append(op.Parameters, *spec.HeaderParam("X-Trace"))
+		},
+	}
+	p := buildPaths(ws, cfg)
+	t.Log(asJSON(p))
+
+	item := p.Paths["/tests/processed"]
+	for _, op := range []*spec.Operation{item.Get, item.Delete} {
+		if op.Extensions["x-method"] == nil {
+			t.Errorf("%s: missing extension", op.ID)
+		}
+		if got, want := op.Parameters[len(op.Parameters)-1].Name, "X-Trace"; got != want {
+			t.Errorf("%s: got %v want %v", op.ID, got, want)
+		}
+	}
+	if got, want := item.Delete.Extensions["x-method"], "DELETE"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestEmitExamples(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/examples")
+	ws.Consumes(restful.MIME_JSON)
+	ws.Produces(restful.MIME_JSON)
+	ws.Route(ws.PUT("").To(dummy).
+		Reads(Sample{}).
+		Returns(200, "ok", []Item{}).
+		Returns(409, "conflict", &ErrorBody{}))
+
+	examples := func(t reflect.Type) interface{} {
+		switch t {
+		case reflect.TypeOf(Sample{}):
+			return Sample{ID: "s1"}
+		case reflect.TypeOf(ErrorBody{}):
+			return ErrorBody{Message: "already exists"}
+		}
+		return nil
+	}
+	p := buildPaths(ws, Config{EmitExamples: true, ExampleFunc: examples})
+	t.Log(asJSON(p))
+
+	put := p.Paths["/tests/examples"].Put
+	if got, want := put.Parameters[0].Schema.Example, (Sample{ID: "s1"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := put.Responses.StatusCodeResponses[409].Schema.Example, (ErrorBody{Message: "already exists"}); got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got := put.Responses.StatusCodeResponses[200].Schema.Example; got != nil {
+		t.Errorf("got %v want nil", got)
+	}
+
+	p = buildPaths(ws, Config{ExampleFunc: examples})
+	if got := p.Paths["/tests/examples"].Put.Parameters[0].Schema.Example; got != nil {
+		t.Errorf("got %v without EmitExamples", got)
+	}
+}
+
+func TestRequestBodyRequiredByDefault(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/bodies")
+	ws.Route(ws.PUT("/default").To(dummy).Reads(Sample{}))
+	ws.Route(ws.PUT("/optional").To(dummy).Reads(Sample{}).Metadata(KeyOpenAPIRequestBodyRequired, false))
+	ws.Route(ws.PUT("/required").To(dummy).Reads(Sample{}).Metadata(KeyOpenAPIRequestBodyRequired, true))
+
+	notRequired := false
+	for _, each := range []struct {
+		byDefault       *bool
+		defaultRequired bool
+	}{
+		{nil, true},
+		{&notRequired, false},
+	} {
+		p := buildPaths(ws, Config{RequestBodyRequiredByDefault: each.byDefault})
+		for path, want := range map[string]bool{
+			"/tests/bodies/default":  each.defaultRequired,
+			"/tests/bodies/optional": false,
+			"/tests/bodies/required": true,
+		} {
+			if got := p.Paths[path].Put.Parameters[0].Required; got != want {
+				t.Errorf("%s: got %v want %v", path, got, want)
+			}
+		}
+	}
+}
+
+func TestLinkBuilderFunc(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/users")
+	ws.Route(ws.POST("").To(dummy).Operation("createUser").Returns(201, "created", Sample{}).Returns(400, "invalid", nil))
+	ws.Route(ws.GET("/{id}").To(dummy).Operation("getUser").Returns(200, "ok", Sample{}))
+
+	cfg := Config{
+		LinkBuilderFunc: func(route restful.Route) map[string]Link {
+			if route.Operation != "createUser" {
+				return nil
+			}
+			return map[string]Link{"GetUser": {
+				OperationID: "getUser",
+				Parameters:  map[string]interface{}{"id": "$response.body#/ID"},
+			}}
+		},
+	}
+	p := buildPaths(ws, cfg)
+	t.Log(asJSON(p))
+
+	data, err := json.Marshal(p.Paths["/tests/users"].Post.Responses.StatusCodeResponses[201].Extensions["x-links"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"GetUser":{"operationId":"getUser","parameters":{"id":"$response.body#/ID"}}}`; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if _, ok := p.Paths["/tests/users"].Post.Responses.StatusCodeResponses[400].Extensions["x-links"]; ok {
+		t.Errorf("unexpected links on error response")
+	}
+	if _, ok := p.Paths["/tests/users/{id}"].Get.Responses.StatusCodeResponses[200].Extensions["x-links"]; ok {
+		t.Errorf("unexpected links without result")
+	}
+}
+
+func TestPathExtensionsFunc(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/lambdas")
+	ws.Route(ws.GET("/{id}").To(dummy))
+	ws.Route(ws.DELETE("/{id}").To(dummy))
+
+	cfg := Config{
+		PathExtensionsFunc: func(path string, method string) map[string]interface{} {
+			if method != http.MethodGet {
+				return nil
+			}
+			return map[string]interface{}{
+				"x-amazon-apigateway-integration": map[string]interface{}{
+					"type":       "aws_proxy",
+					"httpMethod": "POST",
+					"uri":        "arn:aws:apigateway:eu-west-1:lambda:path" + path,
+				},
+				"description": "ignored",
+			}
+		},
+	}
+	p := buildPaths(ws, cfg)
+	t.Log(asJSON(p))
+
+	item := p.Paths["/tests/lambdas/{id}"]
+	integration, ok := item.Get.Extensions["x-amazon-apigateway-integration"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("missing integration in %v", item.Get.Extensions)
+	}
+	if got, want := integration["uri"], "arn:aws:apigateway:eu-west-1:lambda:path/tests/lambdas/{id}"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if _, ok := item.Get.Extensions["description"]; ok {
+		t.Errorf("extension without x- prefix was added")
+	}
+	if len(item.Delete.Extensions) != 0 {
+		t.Errorf("got extensions %v", item.Delete.Extensions)
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/validation")
+	ws.Route(ws.GET("/{id}").To(dummy))
+	ws.Route(ws.POST("/uploads").To(dummy))
+
+	p := buildPaths(ws, Config{RequestValidation: true, RequestValidationExclusions: []string{"/tests/validation/uploads"}})
+	t.Log(asJSON(p))
+
+	validation, ok := p.Paths["/tests/validation/{id}"].Get.Extensions["x-request-validation"].(map[string]interface{})
+	if !ok {
+		t.Fatal("missing x-request-validation")
+	}
+	for _, each := range []string{"validateBody", "validateQuery", "validateHeaders"} {
+		if validation[each] != true {
+			t.Errorf("got %s %v", each, validation[each])
+		}
+	}
+	if _, ok := p.Paths["/tests/validation/uploads"].Post.Extensions["x-request-validation"]; ok {
+		t.Error("excluded path should not be validated")
+	}
+
+	p = buildPaths(ws, Config{})
+	if _, ok := p.Paths["/tests/validation/{id}"].Get.Extensions["x-request-validation"]; ok {
+		t.Error("validation should not be emitted by default")
+	}
+}
+
+func TestResponseValidation(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/validation")
+	ws.Route(ws.GET("/{id}").To(dummy).Returns(200, "ok", Sample{}))
+
+	p := buildPaths(ws, Config{ResponseValidation: true})
+	t.Log(asJSON(p))
+
+	validation, ok := p.Paths["/tests/validation/{id}"].Get.Extensions["x-response-validation"].(map[string]interface{})
+	if !ok {
+		t.Fatal("missing x-response-validation")
+	}
+	if validation["validate"] != true {
+		t.Errorf("got %v", validation)
+	}
+	if _, ok := p.Paths["/tests/validation/{id}"].Get.Extensions["x-request-validation"]; ok {
+		t.Error("request validation should not be emitted")
+	}
+}
+
+func TestRateLimitFunc(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/limits")
+	ws.Route(ws.GET("/{id}").To(dummy).Metadata("rate", 10))
+	ws.Route(ws.DELETE("/{id}").To(dummy))
+
+	cfg := Config{
+		RateLimitFunc: func(r restful.Route) *RateLimit {
+			rate, ok := r.Metadata["rate"].(int)
+			if !ok {
+				return nil
+			}
+			return &RateLimit{RequestsPerSecond: rate, BurstSize: 2 * rate}
+		},
+	}
+	p := buildPaths(ws, cfg)
+	t.Log(asJSON(p))
+
+	item := p.Paths["/tests/limits/{id}"]
+	data, err := json.Marshal(item.Get.Extensions["x-rate-limit"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"requestsPerSecond":10,"burstSize":20}`; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if _, ok := item.Delete.Extensions["x-rate-limit"]; ok {
+		t.Error("operation without rate limit got one")
+	}
+}
+
+type TicketStatus string
+
+type Priority int64
+
+type Ticket struct {
+	Status   TicketStatus `enum:"open|closed"`
+	Priority Priority
+	Labels   []TicketStatus
+}
+
+func TestNamedPrimitiveTypes(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tickets")
+	ws.Route(ws.PUT("/{id}/status").To(dummy).Reads(TicketStatus("")).Returns(200, "OK", Priority(0)))
+	ws.Route(ws.GET("/statuses").To(dummy).Reads([]Priority{}).Returns(200, "OK", []TicketStatus{}))
+	ws.Route(ws.GET("/{id}").To(dummy).Returns(200, "OK", Ticket{}))
+	sw := BuildSwagger(Config{WebServices: []*restful.WebService{ws}})
+
+	put := sw.Paths.Paths["/tickets/{id}/status"].Put
+	body := put.Parameters[len(put.Parameters)-1].Schema
+	if got, want := body.Type[0], "string"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	ok := put.Responses.StatusCodeResponses[200].Schema
+	if got, want := ok.Type[0]+"/"+ok.Format, "integer/int64"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	get := sw.Paths.Paths["/tickets/statuses"].Get
+	if got, want := get.Parameters[0].Schema.Items.Schema.Type[0], "integer"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := get.Responses.StatusCodeResponses[200].Schema.Items.Schema.Type[0], "string"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	for name := range sw.Definitions {
+		if name != "restfulspec.Ticket" {
+			t.Errorf("unexpected definition %s", name)
+		}
+	}
+	ticket := sw.Definitions["restfulspec.Ticket"]
+	status := ticket.Properties["Status"]
+	if got, want := status.Type[0], "string"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := len(status.Enum), 2; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := ticket.Properties["Priority"].Format, "int64"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := ticket.Properties["Labels"].Items.Schema.Type[0], "string"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+type Catalog struct {
+	Title    string    `json:"title"`
+	Products []Product `json:"products"`
+}
+
+type Product struct {
+	Code  string `json:"code" xml:"code,attr"`
+	Price int    `json:"price"`
+}
+
+func TestXMLResponseSchemas(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/catalogs").Produces(restful.MIME_XML)
+	ws.Route(ws.GET("/{id}").To(dummy).Returns(200, "OK", Catalog{}))
+	ws.Route(ws.GET("/names").To(dummy).Returns(200, "OK", []string{}))
+	sw := BuildSwagger(Config{WebServices: []*restful.WebService{ws}})
+
+	catalog := sw.Definitions["restfulspec.Catalog"]
+	if got, want := catalog.XML.Name, "Catalog"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := catalog.Properties["title"].XML.Name, "Title"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	product := sw.Definitions["restfulspec.Product"]
+	if got, want := *product.Properties["code"].XML, (spec.XMLObject{Name: "code", Attribute: true}); got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := product.Properties["price"].XML.Name, "Price"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	names := sw.Paths.Paths["/catalogs/names"].Get.Responses.StatusCodeResponses[200].Schema
+	if got, want := names.Items.Schema.XML.Name, "string"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	ws = new(restful.WebService)
+	ws.Path("/catalogs").Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("/{id}").To(dummy).Returns(200, "OK", Catalog{}))
+	sw = BuildSwagger(Config{WebServices: []*restful.WebService{ws}})
+	if xml := sw.Definitions["restfulspec.Catalog"].XML; xml != nil {
+		t.Errorf("got %v want none", xml)
+	}
+}
+
+func TestSpecPathIsExcluded(t *testing.T) {
+	docs := new(restful.WebService)
+	docs.Path("/apidocs")
+	docs.Route(docs.GET("/").To(dummy))
+	docs.Route(docs.GET("/{tag}").To(dummy))
+	ws := new(restful.WebService)
+	ws.Path("/apidocs-archive")
+	ws.Route(ws.GET("/").To(dummy))
+	sw := BuildSwagger(Config{WebServices: []*restful.WebService{docs, ws}, SpecPath: "/apidocs/"})
+	if got, want := len(sw.Paths.Paths), 1; got != want {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	if _, ok := sw.Paths.Paths["/apidocs-archive"]; !ok {
+		t.Errorf("missing /apidocs-archive in %v", sw.Paths.Paths)
+	}
+}
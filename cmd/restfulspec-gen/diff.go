@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+)
+
+// Exit codes of the diff subcommand.
+const (
+	exitNoChanges   = 0
+	exitBreaking    = 1
+	exitNonBreaking = 2
+	exitDiffFailed  = 3
+)
+
+const diffUsage = "usage: restfulspec-gen diff [--fail-on-breaking] old.json new.json"
+
+// runDiff runs the diff subcommand with its arguments, the flag may follow the files, and returns the exit code.
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	failOnBreaking := flags.Bool("fail-on-breaking", false, "exit 1 for breaking changes and 0 otherwise, for CI")
+	var files []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return exitDiffFailed
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		files = append(files, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+	if len(files) != 2 {
+		fmt.Fprintln(stderr, diffUsage)
+		return exitDiffFailed
+	}
+	code, err := diffSpecs(stdout, files[0], files[1], *failOnBreaking)
+	if err != nil {
+		fmt.Fprintf(stderr, "restfulspec-gen: %v\n", err)
+		return exitDiffFailed
+	}
+	return code
+}
+
+// diffSpecs writes the changes from the old to the new spec file, JSON or YAML, and returns the exit code:
+// exitNoChanges, exitBreaking or exitNonBreaking. With failOnBreaking it is exitBreaking or exitNoChanges.
+func diffSpecs(w io.Writer, oldPath, newPath string, failOnBreaking bool) (int, error) {
+	old, err := restfulspec.LoadBaseSpec(oldPath)
+	if err != nil {
+		return exitDiffFailed, err
+	}
+	current, err := restfulspec.LoadBaseSpec(newPath)
+	if err != nil {
+		return exitDiffFailed, err
+	}
+	changes := restfulspec.DiffSwagger(old, current)
+	breaking := 0
+	for _, each := range changes {
+		if each.Kind == restfulspec.Breaking {
+			breaking++
+		}
+	}
+	for _, each := range changes {
+		fmt.Fprintln(w, each)
+	}
+	fmt.Fprintf(w, "%d breaking, %d non-breaking changes\n", breaking, len(changes)-breaking)
+	switch {
+	case breaking > 0:
+		return exitBreaking, nil
+	case len(changes) > 0 && !failOnBreaking:
+		return exitNonBreaking, nil
+	}
+	return exitNoChanges, nil
+}
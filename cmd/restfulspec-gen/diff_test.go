@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	health := `"/health":{"get":{"responses":{"200":{"description":"ok"}}}}`
+	users := `"/users":{"get":{"responses":{"200":{"description":"ok"}}}}`
+	old := write("old.json", `{"swagger":"2.0","paths":{`+health+`}}`)
+	added := write("added.json", `{"swagger":"2.0","paths":{`+health+`,`+users+`}}`)
+	removed := write("removed.yaml", "swagger: \"2.0\"\npaths: {}\n")
+
+	for _, each := range []struct {
+		args []string
+		want int
+		out  string
+	}{
+		{[]string{old, old}, exitNoChanges, "0 breaking, 0 non-breaking changes"},
+		{[]string{old, added}, exitNonBreaking, "[non-breaking] /users: path added"},
+		{[]string{old, added, "--fail-on-breaking"}, exitNoChanges, "0 breaking, 1 non-breaking changes"},
+		{[]string{old, removed}, exitBreaking, "[breaking] /health: path removed"},
+		{[]string{"--fail-on-breaking", old, removed}, exitBreaking, "1 breaking, 0 non-breaking changes"},
+		{[]string{old}, exitDiffFailed, ""},
+		{[]string{old, filepath.Join(dir, "missing.json")}, exitDiffFailed, ""},
+	} {
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		if got := runDiff(each.args, stdout, stderr); got != each.want {
+			t.Errorf("%v: got exit %d want %d, %s", each.args, got, each.want, stderr)
+		}
+		if !strings.Contains(stdout.String(), each.out) {
+			t.Errorf("%v: missing %q in %s", each.args, each.out, stdout)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// generator builds the spec by running a program that imports the package and calls its spec function.
+type generator struct {
+	pkg      string
+	function string
+	output   string
+}
+
+var mainTemplate = template.Must(template.New("main").Parse(`// Code generated by restfulspec-gen. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	target {{printf "%q" .ImportPath}}
+)
+
+func main() {
+	swagger, err := restfulspec.BuildSwaggerWithError(target.{{.Function}}())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	out, err := os.Create({{printf "%q" .Output}})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	{{if .YAML}}err = restfulspec.WriteSwaggerYAML(out, swagger){{else}}err = restfulspec.WriteSwaggerJSON(out, swagger){{end}}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+// mainSource returns the source of the program that writes the spec of the package with the import path.
+func (g generator) mainSource(importPath, output string) ([]byte, error) {
+	ext := strings.ToLower(filepath.Ext(output))
+	buf := new(bytes.Buffer)
+	err := mainTemplate.Execute(buf, map[string]interface{}{
+		"ImportPath": importPath,
+		"Function":   g.function,
+		"Output":     output,
+		"YAML":       ext == ".yaml" || ext == ".yml",
+	})
+	return buf.Bytes(), err
+}
+
+// generate writes the spec to the output file.
+// The program is run from a temporary directory in the current one such that it uses the same module.
+func (g generator) generate() error {
+	importPath, _, err := g.listPackage()
+	if err != nil {
+		return err
+	}
+	output, err := filepath.Abs(g.output)
+	if err != nil {
+		return err
+	}
+	src, err := g.mainSource(importPath, output)
+	if err != nil {
+		return err
+	}
+	dir, err := os.MkdirTemp(".", ".restfulspec-gen-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), src, 0o644); err != nil {
+		return err
+	}
+	cmd := exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("run spec program for %s: %v", importPath, err)
+	}
+	return nil
+}
+
+// listPackage returns the import path and directory of the package, which can also be given as a directory.
+func (g generator) listPackage() (importPath, dir string, err error) {
+	out, err := exec.Command("go", "list", "-f", "{{.ImportPath}}\n{{.Dir}}", g.pkg).Output()
+	if err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			return "", "", fmt.Errorf("go list %s: %s", g.pkg, bytes.TrimSpace(exit.Stderr))
+		}
+		return "", "", err
+	}
+	lines := strings.SplitN(strings.TrimSpace(string(out)), "\n", 2)
+	if len(lines) != 2 {
+		return "", "", fmt.Errorf("go list %s: unexpected output %q", g.pkg, out)
+	}
+	return lines[0], lines[1], nil
+}
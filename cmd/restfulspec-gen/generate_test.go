@@ -0,0 +1,49 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestMainSource(t *testing.T) {
+	g := generator{function: "BuildSpec"}
+	for _, output := range []string{"/tmp/swagger.json", "/tmp/swagger.yaml"} {
+		src, err := g.mainSource("example.com/api/users", output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "main.go", src, 0); err != nil {
+			t.Fatalf("%v\n%s", err, src)
+		}
+		for _, want := range []string{`target "example.com/api/users"`, "target.BuildSpec()", `"` + output + `"`} {
+			if !strings.Contains(string(src), want) {
+				t.Errorf("missing %s in\n%s", want, src)
+			}
+		}
+		if got, want := strings.Contains(string(src), "WriteSwaggerYAML"), strings.HasSuffix(output, ".yaml"); got != want {
+			t.Errorf("got YAML %v want %v", got, want)
+		}
+	}
+}
+
+func TestIsGoSourceChange(t *testing.T) {
+	for _, each := range []struct {
+		event fsnotify.Event
+		want  bool
+	}{
+		{fsnotify.Event{Name: "/api/users.go", Op: fsnotify.Write}, true},
+		{fsnotify.Event{Name: "/api/users.go", Op: fsnotify.Create}, true},
+		{fsnotify.Event{Name: "/api/users.go", Op: fsnotify.Rename}, true},
+		{fsnotify.Event{Name: "/api/users.go", Op: fsnotify.Chmod}, false},
+		{fsnotify.Event{Name: "/api/swagger.json", Op: fsnotify.Write}, false},
+		{fsnotify.Event{Name: "/api/.restfulspec-gen-123/main.go", Op: fsnotify.Create}, false},
+	} {
+		if got := isGoSourceChange(each.event); got != each.want {
+			t.Errorf("%v: got %v want %v", each.event, got, each.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Command restfulspec-gen writes the OpenAPI spec of a package that uses go-restful to a file.
+//
+// The package must have an exported function, BuildSpec by default, that returns the restfulspec.Config
+// of its services:
+//
+//	func BuildSpec() restfulspec.Config
+//
+// Use it with go generate by adding this line to a file of that package:
+//
+//	//go:generate go run github.com/emicklei/go-restful-openapi/v2/cmd/restfulspec-gen -o swagger.json
+//
+// The spec is written as YAML if the output file ends with .yaml or .yml.
+// With -watch it keeps running and writes the spec again each time a Go file of the package changes.
+//
+// The diff subcommand compares two spec files, JSON or YAML, and lists the changes of DiffSwagger:
+//
+//	restfulspec-gen diff [--fail-on-breaking] old.json new.json
+//
+// It exits with 0 if there are no changes, 1 if some are breaking, 2 if all are non-breaking and 3 on errors.
+// With --fail-on-breaking, for CI, it exits with 0 unless some changes are breaking.
+//
+// The mock subcommand writes a go-restful WebService with a handler for each operation of a spec file,
+// which returns the example of its response, or the zero value of its schema, with 200 OK:
+//
+//	restfulspec-gen mock --spec swagger.json --output ./mocks
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		os.Exit(runDiff(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "mock" {
+		os.Exit(runMock(os.Args[2:], os.Stderr))
+	}
+	g := generator{}
+	flag.StringVar(&g.pkg, "pkg", ".", "import path or directory of the package with the spec function")
+	flag.StringVar(&g.function, "func", "BuildSpec", "name of the function that returns the restfulspec.Config")
+	flag.StringVar(&g.output, "o", "swagger.json", "file to write the spec to, as YAML if it ends with .yaml or .yml")
+	watch := flag.Bool("watch", false, "write the spec again each time a Go file of the package changes")
+	flag.Parse()
+
+	run := g.generate
+	if *watch {
+		run = g.watch
+	}
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "restfulspec-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
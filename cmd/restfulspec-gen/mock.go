@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/go-openapi/spec"
+)
+
+// mockFileName is the name of the file the mock subcommand writes to its output directory.
+const mockFileName = "mock_handlers.go"
+
+// runMock runs the mock subcommand with its arguments and returns the exit code.
+func runMock(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("mock", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	specPath := flags.String("spec", "swagger.json", "spec file, JSON or YAML, to generate the handlers of")
+	output := flags.String("output", "mocks", "directory to write "+mockFileName+" to")
+	pkg := flags.String("package", "", "package name of the handlers, the name of the output directory if empty")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	if err := writeMocks(*specPath, *output, *pkg); err != nil {
+		fmt.Fprintf(stderr, "restfulspec-gen: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeMocks writes the mock handlers of the operations of the spec file to the output directory.
+func writeMocks(specPath, output, pkg string) error {
+	swagger, err := restfulspec.LoadBaseSpec(specPath)
+	if err != nil {
+		return err
+	}
+	if pkg == "" {
+		abs, err := filepath.Abs(output)
+		if err != nil {
+			return err
+		}
+		pkg = packageName(filepath.Base(abs))
+	}
+	src, err := mockSource(swagger, pkg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(output, mockFileName), src, 0644)
+}
+
+// mockRoute is an operation of the spec with the handler that returns its example response.
+type mockRoute struct {
+	Method  string
+	Path    string
+	Handler string
+	ID      string
+	Body    string
+}
+
+var mockTemplate = template.Must(template.New("mock").Parse(`// Code generated by restfulspec-gen mock. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"net/http"
+
+	restful "github.com/emicklei/go-restful/v3"
+)
+
+// NewWebService returns a WebService with a route for each operation of the spec,
+// whose handler returns the example of its response, if any, with 200 OK.
+func NewWebService() *restful.WebService {
+	ws := new(restful.WebService)
+	{{- if .BasePath}}
+	ws.Path({{printf "%q" .BasePath}})
+	{{- end}}
+	ws.Produces(restful.MIME_JSON)
+	{{- range .Routes}}
+	ws.Route(ws.Method({{printf "%q" .Method}}).Path({{printf "%q" .Path}}).To({{.Handler}}){{if .ID}}.Operation({{printf "%q" .ID}}){{end}})
+	{{- end}}
+	return ws
+}
+{{range .Routes}}
+// {{.Handler}} handles {{.Method}} {{.Path}}.
+func {{.Handler}}(req *restful.Request, resp *restful.Response) {
+	writeExample(resp, {{printf "%q" .Body}})
+}
+{{end}}
+func writeExample(resp *restful.Response, body string) {
+	if body == "" {
+		resp.WriteHeader(http.StatusOK)
+		return
+	}
+	resp.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	resp.WriteHeader(http.StatusOK)
+	resp.Write([]byte(body))
+}
+`))
+
+// mockSource returns the formatted source of the mock handlers of the operations of the spec.
+func mockSource(swagger *spec.Swagger, pkg string) ([]byte, error) {
+	routes, err := mockRoutes(swagger)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	err = mockTemplate.Execute(buf, map[string]interface{}{
+		"Package":  pkg,
+		"BasePath": strings.TrimSuffix(swagger.BasePath, "/"),
+		"Routes":   routes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// mockRoutes returns the routes of the operations of the spec, by path and method.
+func mockRoutes(swagger *spec.Swagger) ([]mockRoute, error) {
+	if swagger.Paths == nil {
+		return nil, nil
+	}
+	paths := make([]string, 0, len(swagger.Paths.Paths))
+	for path := range swagger.Paths.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	handlers := map[string]bool{}
+	var routes []mockRoute
+	for _, path := range paths {
+		item := swagger.Paths.Paths[path]
+		for _, each := range []struct {
+			method string
+			op     *spec.Operation
+		}{
+			{"DELETE", item.Delete}, {"GET", item.Get}, {"HEAD", item.Head}, {"OPTIONS", item.Options},
+			{"PATCH", item.Patch}, {"POST", item.Post}, {"PUT", item.Put},
+		} {
+			if each.op == nil {
+				continue
+			}
+			var body []byte
+			if example := exampleResponse(swagger, each.op); example != nil {
+				var err error
+				if body, err = json.Marshal(example); err != nil {
+					return nil, fmt.Errorf("example of %s %s: %v", each.method, path, err)
+				}
+			}
+			name := handlerName(each.op.ID, each.method, path)
+			for i := 2; handlers[name]; i++ {
+				name = handlerName(each.op.ID, each.method, path) + strconv.Itoa(i)
+			}
+			handlers[name] = true
+			routes = append(routes, mockRoute{
+				Method:  each.method,
+				Path:    path,
+				Handler: name,
+				ID:      each.op.ID,
+				Body:    string(body),
+			})
+		}
+	}
+	return routes, nil
+}
+
+// exampleResponse returns the JSON example of the success response of the operation, or else the example
+// or zero value of its schema. It is nil if the operation has no response with a schema.
+func exampleResponse(swagger *spec.Swagger, op *spec.Operation) interface{} {
+	if op.Responses == nil {
+		return nil
+	}
+	var rsp *spec.Response
+	codes := make([]int, 0, len(op.Responses.StatusCodeResponses))
+	for code := range op.Responses.StatusCodeResponses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		if code >= 200 && code < 300 {
+			each := op.Responses.StatusCodeResponses[code]
+			rsp = &each
+			break
+		}
+	}
+	if rsp == nil {
+		rsp = op.Responses.Default
+	}
+	if rsp == nil {
+		return nil
+	}
+	if example, ok := rsp.Examples["application/json"]; ok {
+		return example
+	}
+	if rsp.Schema == nil {
+		return nil
+	}
+	return zeroValue(swagger, rsp.Schema, map[string]bool{})
+}
+
+// zeroValue returns the example, default or else zero value of a JSON value of the schema.
+// Definitions are followed unless they refer to themselves, then the value is nil.
+func zeroValue(swagger *spec.Swagger, s *spec.Schema, visiting map[string]bool) interface{} {
+	if ref := s.Ref.String(); ref != "" {
+		name := strings.TrimPrefix(ref, "#/definitions/")
+		def, ok := swagger.Definitions[name]
+		if !ok || visiting[name] {
+			return nil
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+		return zeroValue(swagger, &def, visiting)
+	}
+	switch {
+	case s.Example != nil:
+		return s.Example
+	case s.Default != nil:
+		return s.Default
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+	case len(s.AllOf) > 0:
+		merged := map[string]interface{}{}
+		for i := range s.AllOf {
+			if each, ok := zeroValue(swagger, &s.AllOf[i], visiting).(map[string]interface{}); ok {
+				for k, v := range each {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	switch {
+	case s.Type.Contains("string"):
+		return ""
+	case s.Type.Contains("integer"), s.Type.Contains("number"):
+		return 0
+	case s.Type.Contains("boolean"):
+		return false
+	case s.Type.Contains("array"):
+		return []interface{}{}
+	case s.Type.Contains("object"), len(s.Properties) > 0:
+		value := map[string]interface{}{}
+		for name, prop := range s.Properties {
+			prop := prop
+			value[name] = zeroValue(swagger, &prop, visiting)
+		}
+		return value
+	}
+	return nil
+}
+
+// handlerName returns the exported Go name of the handler of the operation, from its ID or else its method and path.
+func handlerName(id, method, path string) string {
+	if id == "" {
+		id = strings.ToLower(method) + " " + path
+	}
+	var b strings.Builder
+	upper := true
+	for _, r := range id {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "Handle" + name
+	}
+	return name
+}
+
+// packageName returns the Go package name of a directory name.
+func packageName(dir string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, dir)
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		return "mocks"
+	}
+	return name
+}
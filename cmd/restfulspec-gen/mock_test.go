@@ -0,0 +1,51 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteMocks(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "swagger.json")
+	doc := `{"swagger":"2.0","basePath":"/api/","paths":{
+		"/users/{id}":{"get":{"operationId":"getUser","responses":{"200":{"description":"ok","schema":{"$ref":"#/definitions/User"}}}},
+			"delete":{"responses":{"204":{"description":"deleted"}}}},
+		"/users":{"get":{"operationId":"get-user","responses":{"200":{"description":"ok","examples":{"application/json":[{"name":"ann"}]}}}}}},
+		"definitions":{"User":{"type":"object","properties":{"name":{"type":"string"},"age":{"type":"integer"},"manager":{"$ref":"#/definitions/User"}}}}}`
+	if err := os.WriteFile(specPath, []byte(doc), 0600); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "user-mocks")
+	if err := writeMocks(specPath, output, ""); err != nil {
+		t.Fatal(err)
+	}
+	src, err := os.ReadFile(filepath.Join(output, mockFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), mockFileName, src, 0); err != nil {
+		t.Fatalf("%v\n%s", err, src)
+	}
+	for _, want := range []string{
+		"package usermocks",
+		`ws.Path("/api")`,
+		`ws.Route(ws.Method("GET").Path("/users").To(GetUser).Operation("get-user"))`,
+		`ws.Route(ws.Method("GET").Path("/users/{id}").To(GetUser2).Operation("getUser"))`,
+		`ws.Route(ws.Method("DELETE").Path("/users/{id}").To(DeleteUsersId))`,
+		`writeExample(resp, "[{\"name\":\"ann\"}]")`,
+		`writeExample(resp, "{\"age\":0,\"manager\":null,\"name\":\"\"}")`,
+		`writeExample(resp, "")`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("missing %s in\n%s", want, src)
+		}
+	}
+	if err := writeMocks(filepath.Join(dir, "missing.json"), output, ""); err == nil {
+		t.Error("expected error for missing spec")
+	}
+}
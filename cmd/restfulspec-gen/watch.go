@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDelay is the time to wait for more changes, e.g. from an editor saving several files, before generating.
+const watchDelay = 200 * time.Millisecond
+
+// watch generates the spec and generates it again each time a Go file of the package changes.
+// Errors of a generation are reported and watching continues; it only returns if watching fails.
+func (g generator) watch() error {
+	_, dir, err := g.listPackage()
+	if err != nil {
+		return err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	if err := watcher.Add(dir); err != nil {
+		return fmt.Errorf("watch %s: %v", dir, err)
+	}
+	g.generateAndReport()
+	fmt.Fprintf(os.Stderr, "restfulspec-gen: watching %s\n", dir)
+
+	timer := time.NewTimer(watchDelay)
+	timer.Stop()
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if isGoSourceChange(event) {
+				timer.Reset(watchDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("watch %s: %v", dir, err)
+		case <-timer.C:
+			g.generateAndReport()
+		}
+	}
+}
+
+func (g generator) generateAndReport() {
+	if err := g.generate(); err != nil {
+		fmt.Fprintf(os.Stderr, "restfulspec-gen: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "restfulspec-gen: wrote %s\n", g.output)
+}
+
+// isGoSourceChange returns true if the event changes a Go file other than those of restfulspec-gen itself.
+func isGoSourceChange(event fsnotify.Event) bool {
+	if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+		return false
+	}
+	if filepath.Ext(event.Name) != ".go" {
+		return false
+	}
+	return !strings.HasPrefix(filepath.Base(filepath.Dir(event.Name)), ".restfulspec-gen-")
+}
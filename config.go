@@ -1,6 +1,8 @@
 package restfulspec
 
 import (
+	"io/fs"
+	"log/slog"
 	"reflect"
 
 	"github.com/emicklei/go-restful/v3"
@@ -40,10 +42,16 @@ type Config struct {
 	Host string
 	// [optional] If set then set this field with the generated Swagger Object
 	Schemes []string
-	// WebServicesURL is a DEPRECATED field; it never had any effect in this package.
+	// [optional] If set then set the basePath field of the generated Swagger Object, e.g. /api/v1
+	BasePath string
+	// [optional] WebServicesURL is a convenience to set both Host and BasePath, e.g. http://localhost:8080/api/v1.
+	//   An explicit Host or BasePath takes precedence over the corresponding part of this URL.
 	WebServicesURL string
 	// APIPath is the path where the JSON api is available, e.g. /apidocs.json
 	APIPath string
+	// [optional] Path of the routes that serve the spec, which are left out of it along with those below it,
+	//   e.g. /apidocs.json. RegisterSwaggerService sets it to its path.
+	SpecPath string
 	// api listing is constructed from this list of restful WebServices.
 	WebServices []*restful.WebService
 	// [optional] on default CORS (Cross-Origin-Resource-Sharing) is enabled.
@@ -54,9 +62,326 @@ type Config struct {
 	SchemaFormatHandler MapSchemaFormatFunc
 	// [optional] If set, model builder should call this handler to retrieve the name for a given type.
 	ModelTypeNameHandler MapModelTypeNameFunc
+	// [optional] If set, a deep copy of this Swagger Object, e.g. with info, security definitions and tags,
+	//   is the starting point of the generated one. Generated paths and definitions take precedence over its own.
+	BaseSpec *spec.Swagger
+	// [optional] If set, and BaseSpec is not, the base is read from the Swagger 2.0 JSON or YAML file at BaseSpecPath
+	//   of this file system, such as an embed.FS with a hand-written swagger.yaml. See LoadBaseSpecFS.
+	BaseSpecFS fs.FS
+	// [optional] Path of the base spec in BaseSpecFS, e.g. api/swagger.yaml
+	BaseSpecPath string
 	// [optional] If set then call this function with the generated Swagger Object
 	PostBuildSwaggerObjectHandler PostBuildSwaggerObjectFunc
 	// [optional] If set then call handler's function for to generate name by this handler for definition without json tag,
 	//   you can use you DefinitionNameHandler, also, there are four DefinitionNameHandler provided, see definition_name.go
 	DefinitionNameHandler DefinitionNameHandlerFunc
+	// [optional] If set, call this function with the name of each property, from its json tag or the
+	//   DefinitionNameHandler, and use the name it returns. The handlers of definition_name.go can be used.
+	PropertyNameNormalizer func(string) string
+	// [optional] If set, call this function for each operation without a default response (see DefaultReturns)
+	//   and use a non-nil return value as its "default" response.
+	DefaultResponseFunc func(route restful.Route) *spec.Response
+	// [optional] If set, the schema of this type is used for error responses that have no model of their own.
+	ErrorType interface{}
+	// [optional] If set, these status codes are documented with the ErrorType schema for every operation,
+	//   otherwise all non-2xx responses (including the default) without a model use it.
+	ErrorStatusCodes []int
+	// [optional] If set, each definition of a named Go type gets a x-go-type extension
+	//   that refers to that type, for use by code generators such as oapi-codegen.
+	EmitGoTypeExtension bool
+	// [optional] If set, each definition of a named Go type gets the x-go-package and x-go-type-name extensions.
+	EmitGoTypeNameExtensions bool
+	// [optional] If set, call this function with the type of each definition and use a non-empty result as its $id.
+	SchemaIDFunc func(t reflect.Type) string
+	// [optional] If set, the JSON Schema version, e.g. http://json-schema.org/draft-07/schema#,
+	//   is added as $schema to the definitions or the root extensions, see SchemaVersionPlacement.
+	EmitSchemaVersion string
+	// [optional] Where EmitSchemaVersion is added, on default to each definition.
+	SchemaVersionPlacement SchemaVersionPlacement
+	// [optional] The JSON Schema draft the schemas may use keywords of, e.g. JSONSchemaDraft201909 for the
+	//   unevaluatedProperties and unevaluatedItems tags. JSONSchemaDraft202012 for OpenAPIVersion31 if empty.
+	JSONSchemaDraft string
+	// [optional] If set, call this function with the type of each definition and add the if, then and else
+	//   keywords of a non-nil result to the definition.
+	ConditionalSchemaFunc func(t reflect.Type) *ConditionalSchema
+	// [optional] If set, the number of nested models that are built for a model; models below that depth
+	//   are documented as {type: object} and logged. 0 (default) means unlimited.
+	MaxDefinitionDepth int
+	// [optional] If set, call this function with each definition after it is built and before it is stored.
+	DefinitionPostProcessor func(name string, schema *spec.Schema, t reflect.Type)
+	// [optional] If set, call this function with each route and add the links it returns to its success responses,
+	//   as the x-links extension.
+	LinkBuilderFunc func(route restful.Route) map[string]Link
+	// [optional] If set, call this function with the path and method of each operation and add the
+	//   extensions it returns to the operation. This is the way to add an AWS API Gateway integration, e.g.
+	//   x-amazon-apigateway-integration with the uri of a Lambda function. Keys must start with x-.
+	PathExtensionsFunc func(path string, method string) map[string]interface{}
+	// [optional] If set, each operation gets the AWS API Gateway extensions of a Lambda integration.
+	//   Extensions returned by the PathExtensionsFunc take precedence.
+	AWSAPIGatewayConfig *AWSAPIGatewayConfig
+	// [optional] If set, each operation gets the x-go-handler extension with the name of the function of its route,
+	//   qualified by the import path of its package, e.g. example.com/users.(*UserResource).FindUser.
+	EmitHandlerExtension bool
+	// [optional] If set, each operation gets the x-request-validation extension for proxies that validate
+	//   the body, query and headers of requests against the spec.
+	RequestValidation bool
+	// [optional] Paths, such as /users/{id}, of which the operations do not get the x-request-validation extension.
+	RequestValidationExclusions []string
+	// [optional] If set, each operation gets the x-response-validation extension for contract testing tools
+	//   that validate responses against the spec.
+	ResponseValidation bool
+	// [optional] If set, call this function with each route and add the rate limit it returns, if not nil,
+	//   to its operation as the x-rate-limit extension.
+	RateLimitFunc func(route restful.Route) *RateLimit
+	// [optional] If set, call this function with each route to get the ID of its operation, the Operation of
+	//   the route if it returns an empty string. If not set, OperationIDFromHandler derives the ID from the
+	//   function of routes without an Operation set with RouteBuilder.Operation, see DisableOperationIDFromHandler.
+	RouteToOperationIDFunc func(route restful.Route) string
+	// [optional] If set, routes without RouteToOperationIDFunc keep the Operation that go-restful gives them
+	//   as operation ID instead of the one of OperationIDFromHandler.
+	DisableOperationIDFromHandler bool
+	// [optional] If set, call this function with each operation after it is built and before it is added to its path.
+	OperationPostProcessor func(op *spec.Operation, route restful.Route)
+	// [optional] If set, the schemas of body parameters and responses get an example from the ExampleFunc.
+	EmitExamples bool
+	// [optional] If EmitExamples is set, call this function with each request and response type
+	//   to get the example value of its schema; nil means no example.
+	ExampleFunc func(t reflect.Type) interface{}
+	// [optional] If set to false, body parameters are not required unless the route has the
+	//   KeyOpenAPIRequestBodyRequired Metadata; if nil (default) they are required as declared, which restful defaults to true.
+	RequestBodyRequiredByDefault *bool
+	// [optional] If set to false, types that implement encoding.TextMarshaler are documented by their fields
+	//   instead of as a string, the way encoding/json encodes them. Nil means true.
+	HonorTextMarshaler *bool
+	// [optional] If set to false, unexported struct fields are documented as properties, named like exported fields.
+	//   Nil means true: they are omitted, as encoding/json does, except for the fields of embedded structs.
+	OmitUnexportedFields *bool
+	// [optional] If set, the common types of the pgtype package of pgx, such as pgtype.Text, pgtype.Int4 and
+	//   pgtype.Timestamptz, are documented as the primitive of their JSON value with x-nullable.
+	PgtypeMapping bool
+	// [optional] Schemas of types, typically implementing json.Marshaler, to use instead of those derived from the type.
+	//   A json.Marshaler without schema is documented as any value, with a warning, unless the SchemaFormatHandler
+	//   gives it a format, such as that of time.Time.
+	JSONMarshalerOverrides map[reflect.Type]*spec.Schema
+	// [optional] Types of arbitrary-precision decimals, such as decimal.Decimal of shopspring/decimal,
+	//   that are documented as a number with the decimal format.
+	DecimalTypes []reflect.Type
+	// [optional] How the DecimalTypes are encoded: DecimalFormatNumber, the default if empty, or DecimalFormatString.
+	DecimalFormat string
+	// [optional] Returns the schema of the values of fields of an interface type, such as io.Reader or
+	//   a domain-specific Repository, whose values can be of any type that implements it.
+	//   If not set, or if it returns nil, such values are documented as any value ({}). For example:
+	//     func(t reflect.Type) *spec.Schema {
+	//         if t == reflect.TypeOf((*io.Reader)(nil)).Elem() {
+	//             return spec.StrFmtProperty("binary")
+	//         }
+	//         return nil
+	//     }
+	InterfaceSchemaFunc func(t reflect.Type) *spec.Schema
+	// [optional] Returns the enum values of a named primitive type, such as type Status string, or nil if it has none.
+	//   Without values of this function, those of a Values method of the type that returns a slice of the type are used.
+	//   Package-level functions cannot be found by reflection, register them here by type. For example:
+	//     func(t reflect.Type) []interface{} {
+	//         if t == reflect.TypeOf(Status("")) {
+	//             return []interface{}{"active", "inactive"}
+	//         }
+	//         return nil
+	//     }
+	EnumValueFunc func(t reflect.Type) []interface{}
+	// [optional] Returns the description of the definition of a type that has none from a SwaggerDoc method
+	//   or modelDescription tags, typically its doc comment. GoDocDescription reads it from the source of the package.
+	GoDocProvider func(t reflect.Type) string
+	// [optional] Returns the description of a property of a struct field that has no description tag,
+	//   typically its comment. GoDocFieldDescriptions reads it from the source of the packages.
+	FieldDescriptionFunc func(field reflect.StructField) string
+	// [optional] If set, the k8s_list_type, k8s_list_map_keys and k8s_preserve_unknown_fields tags of fields are
+	//   translated to the x-kubernetes-list-type, x-kubernetes-list-map-keys and x-kubernetes-preserve-unknown-fields
+	//   extensions of Kubernetes CustomResourceDefinition schemas.
+	KubernetesExtensionMode bool
+	// [optional] If set, operations of routes with the KeyOpenAPIInternal Metadata are not documented.
+	StripInternalOperations bool
+	// [optional] If set, the properties of all definitions are serialized in alphabetical order,
+	//   ignoring any x-order extension, and their required lists are sorted.
+	SortDefinitionProperties bool
+	// [optional] If set, the documents served by NewOpenAPIService and ServeSwaggerUI list the paths alphabetically
+	//   segment by segment, ignoring case, so that a path comes right before its subpaths. Otherwise they are
+	//   in the byte order of encoding/json, e.g. /Zoo before /apple and /pets-archive before /pets/{id}.
+	SortPaths bool
+	// [optional] Order of the properties of the definitions of structs, see PropertySortMode. Default is SortNone.
+	PropertySort PropertySortMode
+	// [optional] If set, no schema of the generated Swagger Object has additionalProperties false, such as those of the
+	//   BaseSpec, JSONMarshalerOverrides or DefinitionPostProcessor. Applied before the PostBuildSwaggerObjectHandler is called.
+	AllowAdditionalPropertiesOnAll bool
+	// [optional] If set, inline schemas that occur more than once in the definitions, such as enums,
+	//   are replaced by a reference to a shared definition, see SchemaRegistry.
+	EnableSchemaDeduplication bool
+	// [optional] If set, each enum schema of the definitions gets a x-ms-enum extension for AutoRest,
+	//   named after its definition or else its property, with modelAsString false.
+	EmitMSEnumExtension bool
+	// [optional] Title of the Swagger UI page served by ServeSwaggerUI, DefaultSwaggerUITitle if empty.
+	SwaggerUITitle string
+	// [optional] Path where ServeSwaggerUI serves the Swagger UI, DefaultSwaggerUIPrefix if empty.
+	SwaggerUIPrefix string
+	// [optional] If set, the service of NewOpenAPIService also serves a spec for each operation tag
+	//   on <APIPath>/<tag>, see BuildSwaggerPerTag.
+	SplitByTag bool
+	// [optional] If set, BuildSwaggerWithError returns an AggregateError with all problems found while building,
+	//   such as references to types without schema, unregistered protobuf enums and definition names used by
+	//   more than one type, instead of a best-effort Swagger object. BuildSwagger logs the error.
+	StrictMode bool
+	// [optional] If set, BuildSwaggerWithError returns an error listing all properties, as DefinitionName.PropertyName,
+	//   that have no description. BuildSwagger logs the error.
+	RequireAllPropertiesDescribed bool
+	// [optional] Names of the definitions of which the properties need no description, see RequireAllPropertiesDescribed.
+	RequireDescriptionExclusions []string
+	// [optional] If set, warnings are sent to this Logger instead of the logger of go-restful, see StderrLogger and NopLogger.
+	Logger Logger
+	// [optional] If set, warnings are sent to this logger at the warn level, fields as attributes. Takes precedence over Logger.
+	SlogLogger *slog.Logger
+	// [optional] If set, MergeSwaggerWithConfig replaces an operation of the base with the operation
+	//   of the overlay for the same path and method instead of returning an error.
+	AllowPathMergeConflicts bool
+	// [optional] The number of times ResolveRefsWithConfig inlines a definition that refers to itself,
+	//   directly or indirectly, before the $ref is left as is. DefaultResolveRefsDepth if zero.
+	ResolveRefsDepth int
+	// [optional] The version of OpenAPI to use the keywords of, OpenAPIVersion2 if empty. With OpenAPIVersion3 or
+	//   OpenAPIVersion31 schemas use the keywords of OpenAPI 3, such as nullable instead of x-nullable, and the
+	//   service of NewOpenAPIService serves the OpenAPI 3 document of ConvertToOpenAPI3.
+	OpenAPIVersion string
+	// [optional] How nullable schemas are represented, see NullableStrategy.
+	NullableStrategy NullableStrategy
+	// [optional] If set, the service of NewOpenAPIService serves the spec as YAML (MIME_YAML) instead of JSON.
+	ServeAsYAML bool
+	// [optional] If set, these vendor extensions are added to the generated Swagger Object
+	//   before the PostBuildSwaggerObjectHandler is called. All keys must start with x-.
+	GlobalExtensions map[string]interface{}
+	// [optional] If set, the info of the generated Swagger Object gets a x-logo extension, as rendered by Redoc.
+	//   It is added before the PostBuildSwaggerObjectHandler is called.
+	Logo *LogoInfo
+	// [optional] If set, the generated Swagger Object gets a x-tagGroups extension, as rendered by Redoc.
+	//   It is added before the PostBuildSwaggerObjectHandler is called; tags that are not defined
+	//   in the swagger tags by then, e.g. by the BaseSpec, are logged.
+	XTagGroups []TagGroup
+
+	// diagnostics collects the problems while building, set by buildSwagger
+	diagnostics *diagnostics
+}
+
+// TagGroup is an element of the x-tagGroups extension of the Swagger object.
+type TagGroup struct {
+	// Name of the group
+	Name string `json:"name"`
+	// Tags in the group, by name
+	Tags []string `json:"tags"`
+}
+
+// SchemaVersionPlacement tells where Config.EmitSchemaVersion is added to the generated Swagger Object.
+type SchemaVersionPlacement int
+
+const (
+	// SchemaVersionOnDefinitions adds $schema to each definition.
+	SchemaVersionOnDefinitions SchemaVersionPlacement = iota
+	// SchemaVersionOnRoot adds $schema to the extensions of the Swagger Object.
+	// The spec package only serializes extensions prefixed by x-, so $schema is written to the root
+	// of the documents that this package serves and writes, but not by json.Marshal of the Swagger Object.
+	SchemaVersionOnRoot
+)
+
+// PropertySortMode tells how Config.PropertySort orders the properties of definitions.
+// The spec package serializes properties with a x-order extension first, in its order, and the others by name.
+type PropertySortMode int
+
+const (
+	// SortNone leaves the order to the x-order extensions the properties may have.
+	SortNone PropertySortMode = iota
+	// SortAlpha orders the properties by name, their x-order extensions are removed.
+	SortAlpha
+	// SortByTag orders the properties by the integer of the x-order tag of their field, e.g. `x-order:"1"`.
+	// Properties of fields without the tag follow, by name.
+	SortByTag
+)
+
+// NullableStrategy tells how Config.NullableStrategy represents nullable schemas,
+// those of pointers to primitives and of fields with a x-nullable tag.
+type NullableStrategy int
+
+const (
+	// NullableExtension adds the x-nullable extension of Swagger 2 tools.
+	// It is the default unless Config.OpenAPIVersion is OpenAPI 3, then NullableKeyword is.
+	NullableExtension NullableStrategy = iota
+	// NullableKeyword sets the nullable keyword of OpenAPI 3.0. It requires Config.OpenAPIVersion to be OpenAPI 3,
+	// otherwise NullableExtension is used.
+	NullableKeyword
+	// NullableOneOf replaces the schema by oneOf the schema and {type: null}, as JSON Schema does.
+	// Its title and description stay with the property. It requires Config.OpenAPIVersion to be OpenAPIVersion31,
+	// as Swagger 2.0 has no oneOf and OpenAPI 3.0 has no null type; otherwise NullableExtension is used for Swagger 2.0
+	// and NullableKeyword for OpenAPI 3.0.
+	NullableOneOf
+)
+
+// Values of Config.OpenAPIVersion.
+const (
+	OpenAPIVersion2  = "2.0"
+	OpenAPIVersion3  = "3.0"
+	OpenAPIVersion31 = "3.1"
+)
+
+// isOpenAPI3 returns true if the OpenAPIVersion of the config is 3.0 or 3.1.
+func isOpenAPI3(config Config) bool {
+	return config.OpenAPIVersion == OpenAPIVersion3 || config.OpenAPIVersion == OpenAPIVersion31
+}
+
+// Values of Config.JSONSchemaDraft.
+const (
+	JSONSchemaDraft04     = "draft-04"
+	JSONSchemaDraft06     = "draft-06"
+	JSONSchemaDraft07     = "draft-07"
+	JSONSchemaDraft201909 = "2019-09"
+	JSONSchemaDraft202012 = "2020-12"
+)
+
+// jsonSchemaDraftAtLeast returns true if the JSONSchemaDraft of the config is the draft or a later one.
+func jsonSchemaDraftAtLeast(config Config, draft string) bool {
+	drafts := []string{JSONSchemaDraft04, JSONSchemaDraft06, JSONSchemaDraft07, JSONSchemaDraft201909, JSONSchemaDraft202012}
+	current := config.JSONSchemaDraft
+	if current == "" && config.OpenAPIVersion == OpenAPIVersion31 {
+		current = JSONSchemaDraft202012
+	}
+	for i, each := range drafts {
+		if each == current {
+			for _, earlier := range drafts[:i+1] {
+				if earlier == draft {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Values of Config.DecimalFormat.
+const (
+	// DecimalFormatNumber documents the DecimalTypes as {type: number, format: decimal}.
+	DecimalFormatNumber = "decimal"
+	// DecimalFormatString documents the DecimalTypes as {type: string, format: decimal},
+	// for decimals that encode as a JSON string.
+	DecimalFormatString = "string"
+)
+
+// ConditionalSchema holds the schemas of the if, then and else keywords of JSON Schema draft-07.
+// If the instance is valid against If then it must be valid against Then, otherwise against Else.
+type ConditionalSchema struct {
+	If   *spec.Schema
+	Then *spec.Schema
+	Else *spec.Schema
+}
+
+// LogoInfo is the value of the x-logo extension of the Swagger info object.
+type LogoInfo struct {
+	// URL of the logo image
+	URL string `json:"url"`
+	// AltText of the logo image
+	AltText string `json:"altText,omitempty"`
+	// BackgroundColor of the logo, as a CSS color value
+	BackgroundColor string `json:"backgroundColor,omitempty"`
 }
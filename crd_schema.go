@@ -0,0 +1,107 @@
+package restfulspec
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/go-openapi/spec"
+)
+
+// GenerateCRDSchema returns the schema of the type for the validation of a Kubernetes CustomResourceDefinition.
+// The definitions it refers to are inlined because such schemas cannot have a $ref, x-nullable is replaced by nullable,
+// nodes without a type, such as those of interface{} fields and json.Marshaler types, get
+// x-kubernetes-preserve-unknown-fields and the k8s_ tags of KubernetesExtensionMode are used.
+// It returns a JSONSchemaProps, which mirrors that of the k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1
+// package to not depend on Kubernetes modules, see toJSONSchemaProps for the mapping.
+// It returns an error if the type has no definition, such as a primitive type, if it refers to itself
+// or if a schema has the null type, as for NullableOneOf, or more than one type, which structural schemas do not allow.
+func GenerateCRDSchema(t reflect.Type, config Config) (*JSONSchemaProps, error) {
+	config.KubernetesExtensionMode = true
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	b := definitionBuilder{Definitions: spec.Definitions{}, Config: config}
+	b.addModel(t, "")
+	name := keyFrom(t, config)
+	def, ok := b.Definitions[name]
+	if !ok {
+		return nil, fmt.Errorf("no definition for type %s", t)
+	}
+	schema, err := cloneSchema(def)
+	if err != nil {
+		return nil, fmt.Errorf("copy definition %q: %v", name, err)
+	}
+	r := refResolver{definitions: b.Definitions, depth: 1, visiting: map[string]int{name: 1}}
+	if err := r.inline(&schema); err != nil {
+		return nil, err
+	}
+	if err := toStructuralSchema(&schema, false); err != nil {
+		return nil, fmt.Errorf("type %s: %v", t, err)
+	}
+	props, err := toJSONSchemaProps(schema)
+	if err != nil {
+		return nil, fmt.Errorf("type %s: %v", t, err)
+	}
+	return &props, nil
+}
+
+// toStructuralSchema replaces the x-nullable extensions of the schema and its subschemas by nullable and gives
+// those without a type the object or array type of their keywords or else x-kubernetes-preserve-unknown-fields,
+// unless they are in allOf, anyOf, oneOf or not, which need no type. It returns an error for a $ref that is left, which is a type that refers to itself,
+// and for the null type.
+func toStructuralSchema(s *spec.Schema, inJunctor bool) error {
+	if s == nil {
+		return nil
+	}
+	if ref := s.Ref.String(); ref != "" {
+		return fmt.Errorf("recursive reference %s cannot be inlined", ref)
+	}
+	if containsString(s.Type, "null") {
+		return fmt.Errorf("the null type is not allowed in a structural schema, use NullableKeyword instead of NullableOneOf")
+	}
+	if nullable, ok := s.Extensions["x-nullable"].(bool); ok {
+		s.Nullable = nullable
+		delete(s.Extensions, "x-nullable")
+	}
+	if len(s.Type) == 0 && !inJunctor && s.Extensions["x-kubernetes-int-or-string"] != true {
+		switch {
+		case len(s.Properties) > 0 || s.AdditionalProperties != nil:
+			s.Type = spec.StringOrArray{"object"}
+		case s.Items != nil:
+			s.Type = spec.StringOrArray{"array"}
+		default:
+			if _, ok := s.Extensions["x-kubernetes-preserve-unknown-fields"]; !ok {
+				s.AddExtension("x-kubernetes-preserve-unknown-fields", true)
+			}
+		}
+	}
+	for name, each := range s.Properties {
+		if err := toStructuralSchema(&each, false); err != nil {
+			return err
+		}
+		s.Properties[name] = each
+	}
+	if s.Items != nil {
+		if err := toStructuralSchema(s.Items.Schema, false); err != nil {
+			return err
+		}
+		for i := range s.Items.Schemas {
+			if err := toStructuralSchema(&s.Items.Schemas[i], false); err != nil {
+				return err
+			}
+		}
+	}
+	if s.AdditionalProperties != nil {
+		if err := toStructuralSchema(s.AdditionalProperties.Schema, false); err != nil {
+			return err
+		}
+	}
+	for _, list := range [][]spec.Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for i := range list {
+			if err := toStructuralSchema(&list[i], true); err != nil {
+				return err
+			}
+		}
+	}
+	return toStructuralSchema(s.Not, true)
+}
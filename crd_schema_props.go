@@ -0,0 +1,281 @@
+package restfulspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// JSONSchemaProps mirrors the JSONSchemaProps of the k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1 package
+// field for field, with the same JSON names, so that its JSON can be unmarshaled into that type.
+// The JSON values of default, enum and example are interface{} values instead of apiextensions JSON values.
+type JSONSchemaProps struct {
+	ID                     string                                  `json:"id,omitempty"`
+	Schema                 string                                  `json:"$schema,omitempty"`
+	Ref                    *string                                 `json:"$ref,omitempty"`
+	Description            string                                  `json:"description,omitempty"`
+	Type                   string                                  `json:"type,omitempty"`
+	Format                 string                                  `json:"format,omitempty"`
+	Title                  string                                  `json:"title,omitempty"`
+	Default                interface{}                             `json:"default,omitempty"`
+	Maximum                *float64                                `json:"maximum,omitempty"`
+	ExclusiveMaximum       bool                                    `json:"exclusiveMaximum,omitempty"`
+	Minimum                *float64                                `json:"minimum,omitempty"`
+	ExclusiveMinimum       bool                                    `json:"exclusiveMinimum,omitempty"`
+	MaxLength              *int64                                  `json:"maxLength,omitempty"`
+	MinLength              *int64                                  `json:"minLength,omitempty"`
+	Pattern                string                                  `json:"pattern,omitempty"`
+	MaxItems               *int64                                  `json:"maxItems,omitempty"`
+	MinItems               *int64                                  `json:"minItems,omitempty"`
+	UniqueItems            bool                                    `json:"uniqueItems,omitempty"`
+	MultipleOf             *float64                                `json:"multipleOf,omitempty"`
+	Enum                   []interface{}                           `json:"enum,omitempty"`
+	MaxProperties          *int64                                  `json:"maxProperties,omitempty"`
+	MinProperties          *int64                                  `json:"minProperties,omitempty"`
+	Required               []string                                `json:"required,omitempty"`
+	Items                  *JSONSchemaPropsOrArray                 `json:"items,omitempty"`
+	AllOf                  []JSONSchemaProps                       `json:"allOf,omitempty"`
+	OneOf                  []JSONSchemaProps                       `json:"oneOf,omitempty"`
+	AnyOf                  []JSONSchemaProps                       `json:"anyOf,omitempty"`
+	Not                    *JSONSchemaProps                        `json:"not,omitempty"`
+	Properties             map[string]JSONSchemaProps              `json:"properties,omitempty"`
+	AdditionalProperties   *JSONSchemaPropsOrBool                  `json:"additionalProperties,omitempty"`
+	PatternProperties      map[string]JSONSchemaProps              `json:"patternProperties,omitempty"`
+	Dependencies           map[string]JSONSchemaPropsOrStringArray `json:"dependencies,omitempty"`
+	AdditionalItems        *JSONSchemaPropsOrBool                  `json:"additionalItems,omitempty"`
+	Definitions            map[string]JSONSchemaProps              `json:"definitions,omitempty"`
+	ExternalDocs           *ExternalDocumentation                  `json:"externalDocs,omitempty"`
+	Example                interface{}                             `json:"example,omitempty"`
+	Nullable               bool                                    `json:"nullable,omitempty"`
+	XPreserveUnknownFields *bool                                   `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	XEmbeddedResource      bool                                    `json:"x-kubernetes-embedded-resource,omitempty"`
+	XIntOrString           bool                                    `json:"x-kubernetes-int-or-string,omitempty"`
+	XListMapKeys           []string                                `json:"x-kubernetes-list-map-keys,omitempty"`
+	XListType              *string                                 `json:"x-kubernetes-list-type,omitempty"`
+	XMapType               *string                                 `json:"x-kubernetes-map-type,omitempty"`
+	XValidations           []ValidationRule                        `json:"x-kubernetes-validations,omitempty"`
+}
+
+// JSONSchemaPropsOrArray is the schema or the list of schemas of items.
+type JSONSchemaPropsOrArray struct {
+	Schema      *JSONSchemaProps
+	JSONSchemas []JSONSchemaProps
+}
+
+// MarshalJSON encodes the schema or else the list of schemas.
+func (s JSONSchemaPropsOrArray) MarshalJSON() ([]byte, error) {
+	if s.Schema != nil {
+		return json.Marshal(s.Schema)
+	}
+	if s.JSONSchemas == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(s.JSONSchemas)
+}
+
+// JSONSchemaPropsOrBool is the schema or the boolean of additionalProperties and additionalItems.
+type JSONSchemaPropsOrBool struct {
+	Allows bool
+	Schema *JSONSchemaProps
+}
+
+// MarshalJSON encodes the schema or else the boolean.
+func (s JSONSchemaPropsOrBool) MarshalJSON() ([]byte, error) {
+	if s.Schema != nil {
+		return json.Marshal(s.Schema)
+	}
+	return json.Marshal(s.Allows)
+}
+
+// JSONSchemaPropsOrStringArray is the schema or the list of property names of a dependency.
+type JSONSchemaPropsOrStringArray struct {
+	Schema   *JSONSchemaProps
+	Property []string
+}
+
+// MarshalJSON encodes the property names or else the schema.
+func (s JSONSchemaPropsOrStringArray) MarshalJSON() ([]byte, error) {
+	if len(s.Property) > 0 {
+		return json.Marshal(s.Property)
+	}
+	if s.Schema != nil {
+		return json.Marshal(s.Schema)
+	}
+	return []byte("null"), nil
+}
+
+// ExternalDocumentation mirrors the ExternalDocumentation of the apiextensions v1 package.
+type ExternalDocumentation struct {
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// ValidationRule mirrors the ValidationRule of x-kubernetes-validations in the apiextensions v1 package.
+type ValidationRule struct {
+	Rule              string  `json:"rule"`
+	Message           string  `json:"message,omitempty"`
+	MessageExpression string  `json:"messageExpression,omitempty"`
+	Reason            *string `json:"reason,omitempty"`
+	FieldPath         string  `json:"fieldPath,omitempty"`
+	OptionalOldSelf   *bool   `json:"optionalOldSelf,omitempty"`
+}
+
+// toJSONSchemaProps converts a structural schema. The keywords map to the fields of the same JSON name,
+// the single type to Type and the x-kubernetes- extensions to the X fields. Other extensions
+// and the keywords that JSONSchemaProps does not have, such as readOnly, xml and discriminator, are dropped.
+// It returns an error for a schema with more than one type or an x-kubernetes- extension of the wrong type.
+func toJSONSchemaProps(s spec.Schema) (JSONSchemaProps, error) {
+	var props JSONSchemaProps
+	if len(s.Type) > 1 {
+		return props, fmt.Errorf("multiple types %v are not allowed in a structural schema", s.Type)
+	}
+	if len(s.Type) == 1 {
+		props.Type = s.Type[0]
+	}
+	kubernetes := map[string]interface{}{}
+	for name, value := range s.Extensions {
+		if strings.HasPrefix(name, "x-kubernetes-") {
+			kubernetes[name] = value
+		}
+	}
+	if len(kubernetes) > 0 {
+		data, err := json.Marshal(kubernetes)
+		if err == nil {
+			err = json.Unmarshal(data, &props)
+		}
+		if err != nil {
+			return props, fmt.Errorf("kubernetes extensions %v: %v", kubernetes, err)
+		}
+	}
+	props.ID = s.ID
+	props.Schema = string(s.Schema)
+	props.Description = s.Description
+	props.Format = s.Format
+	props.Title = s.Title
+	props.Default = s.Default
+	props.Maximum = s.Maximum
+	props.ExclusiveMaximum = s.ExclusiveMaximum
+	props.Minimum = s.Minimum
+	props.ExclusiveMinimum = s.ExclusiveMinimum
+	props.MaxLength = s.MaxLength
+	props.MinLength = s.MinLength
+	props.Pattern = s.Pattern
+	props.MaxItems = s.MaxItems
+	props.MinItems = s.MinItems
+	props.UniqueItems = s.UniqueItems
+	props.MultipleOf = s.MultipleOf
+	props.Enum = s.Enum
+	props.MaxProperties = s.MaxProperties
+	props.MinProperties = s.MinProperties
+	props.Required = s.Required
+	props.Example = s.Example
+	props.Nullable = s.Nullable
+	if s.ExternalDocs != nil {
+		props.ExternalDocs = &ExternalDocumentation{Description: s.ExternalDocs.Description, URL: s.ExternalDocs.URL}
+	}
+
+	var err error
+	if s.Items != nil {
+		props.Items = &JSONSchemaPropsOrArray{}
+		if s.Items.Schema != nil {
+			if props.Items.Schema, err = toJSONSchemaPropsPtr(s.Items.Schema); err != nil {
+				return props, err
+			}
+		}
+		if props.Items.JSONSchemas, err = toJSONSchemaPropsList(s.Items.Schemas); err != nil {
+			return props, err
+		}
+	}
+	if props.AllOf, err = toJSONSchemaPropsList(s.AllOf); err != nil {
+		return props, err
+	}
+	if props.OneOf, err = toJSONSchemaPropsList(s.OneOf); err != nil {
+		return props, err
+	}
+	if props.AnyOf, err = toJSONSchemaPropsList(s.AnyOf); err != nil {
+		return props, err
+	}
+	if props.Not, err = toJSONSchemaPropsPtr(s.Not); err != nil {
+		return props, err
+	}
+	if props.Properties, err = toJSONSchemaPropsMap(s.Properties); err != nil {
+		return props, err
+	}
+	if props.AdditionalProperties, err = toJSONSchemaPropsOrBool(s.AdditionalProperties); err != nil {
+		return props, err
+	}
+	if props.PatternProperties, err = toJSONSchemaPropsMap(s.PatternProperties); err != nil {
+		return props, err
+	}
+	if props.AdditionalItems, err = toJSONSchemaPropsOrBool(s.AdditionalItems); err != nil {
+		return props, err
+	}
+	if props.Definitions, err = toJSONSchemaPropsMap(s.Definitions); err != nil {
+		return props, err
+	}
+	if len(s.Dependencies) > 0 {
+		props.Dependencies = map[string]JSONSchemaPropsOrStringArray{}
+		for name, each := range s.Dependencies {
+			dependency := JSONSchemaPropsOrStringArray{Property: each.Property}
+			if dependency.Schema, err = toJSONSchemaPropsPtr(each.Schema); err != nil {
+				return props, err
+			}
+			props.Dependencies[name] = dependency
+		}
+	}
+	return props, nil
+}
+
+func toJSONSchemaPropsPtr(s *spec.Schema) (*JSONSchemaProps, error) {
+	if s == nil {
+		return nil, nil
+	}
+	props, err := toJSONSchemaProps(*s)
+	if err != nil {
+		return nil, err
+	}
+	return &props, nil
+}
+
+func toJSONSchemaPropsList(list []spec.Schema) ([]JSONSchemaProps, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	result := make([]JSONSchemaProps, len(list))
+	for i, each := range list {
+		props, err := toJSONSchemaProps(each)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = props
+	}
+	return result, nil
+}
+
+func toJSONSchemaPropsMap(m map[string]spec.Schema) (map[string]JSONSchemaProps, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	result := map[string]JSONSchemaProps{}
+	for name, each := range m {
+		props, err := toJSONSchemaProps(each)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		result[name] = props
+	}
+	return result, nil
+}
+
+func toJSONSchemaPropsOrBool(s *spec.SchemaOrBool) (*JSONSchemaPropsOrBool, error) {
+	if s == nil {
+		return nil, nil
+	}
+	schema, err := toJSONSchemaPropsPtr(s.Schema)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONSchemaPropsOrBool{Allows: s.Allows, Schema: schema}, nil
+}
@@ -0,0 +1,120 @@
+package restfulspec
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type CronTabSpec struct {
+	Schedule string           `json:"schedule" description:"cron schedule"`
+	Replicas *int32           `json:"replicas,omitempty"`
+	Jobs     []CronTabJob     `json:"jobs" k8s_list_type:"map" k8s_list_map_keys:"name"`
+	Template map[string]Image `json:"template"`
+}
+
+type CronTabJob struct {
+	Name  string `json:"name"`
+	Image Image  `json:"image"`
+}
+
+type Image struct {
+	Repository string `json:"repository"`
+}
+
+type CronTabStatus struct {
+	Details interface{}     `json:"details"`
+	Raw     json.RawMessage `json:"raw"`
+	Count   int             `json:"count"`
+}
+
+type CronTabTree struct {
+	Children []CronTabTree
+}
+
+func TestGenerateCRDSchema(t *testing.T) {
+	schema, err := GenerateCRDSchema(reflect.TypeOf(&CronTabSpec{}), Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(schema)
+	if strings.Contains(string(data), "$ref") || strings.Contains(string(data), "x-nullable") {
+		t.Errorf("got %s", data)
+	}
+	if got, want := schema.Properties["replicas"].Nullable, true; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	jobs := schema.Properties["jobs"]
+	if got, want := *jobs.XListType, "map"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	image := jobs.Items.Schema.Properties["image"]
+	if got, want := image.Properties["repository"].Type, "string"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := schema.Properties["template"].AdditionalProperties.Schema.Properties["repository"].Type, "string"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	if _, err := GenerateCRDSchema(reflect.TypeOf(CronTabSpec{}), Config{NullableStrategy: NullableOneOf, OpenAPIVersion: OpenAPIVersion31}); err == nil {
+		t.Error("expected error for NullableOneOf")
+	}
+	if _, err := GenerateCRDSchema(reflect.TypeOf(CronTabTree{}), Config{}); err == nil {
+		t.Error("expected error for recursive type")
+	}
+	if _, err := GenerateCRDSchema(reflect.TypeOf(""), Config{}); err == nil {
+		t.Error("expected error for primitive type")
+	}
+}
+
+func TestGenerateCRDSchemaPreservesUnknownFields(t *testing.T) {
+	schema, err := GenerateCRDSchema(reflect.TypeOf(CronTabStatus{}), Config{Logger: NopLogger{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]bool{"details": true, "raw": true, "count": false} {
+		if got := schema.Properties[name].XPreserveUnknownFields; (got != nil) != want || got != nil && !*got {
+			t.Errorf("%s: got %v want %v", name, got, want)
+		}
+	}
+	if got, want := schema.Type, "object"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestGenerateCRDSchemaJSON(t *testing.T) {
+	schema, err := GenerateCRDSchema(reflect.TypeOf(CronTabSpec{}), Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Type       string
+		Required   []string
+		Properties map[string]struct {
+			Type                 string
+			Nullable             bool
+			ListType             string   `json:"x-kubernetes-list-type"`
+			ListMapKeys          []string `json:"x-kubernetes-list-map-keys"`
+			Items                struct{ Type string }
+			AdditionalProperties struct{ Type string }
+		}
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	jobs := doc.Properties["jobs"]
+	if got, want := doc.Type+" "+jobs.Type+" "+jobs.Items.Type+" "+doc.Properties["template"].AdditionalProperties.Type, "object array object object"; got != want {
+		t.Errorf("got %v want %v in %s", got, want, data)
+	}
+	if got, want := jobs.ListType+" "+strings.Join(jobs.ListMapKeys, ","), "map name"; got != want {
+		t.Errorf("got %v want %v in %s", got, want, data)
+	}
+	if !doc.Properties["replicas"].Nullable {
+		t.Errorf("replicas not nullable in %s", data)
+	}
+}
@@ -1,6 +1,7 @@
 package restfulspec
 
 import (
+	"encoding"
 	"encoding/json"
 	"github.com/emicklei/go-restful/v3"
 	"reflect"
@@ -13,6 +14,8 @@ import (
 type definitionBuilder struct {
 	Definitions spec.Definitions
 	Config      Config
+	// depth is the number of models being built that lead to the next one, see Config.MaxDefinitionDepth
+	depth int
 }
 
 // Documented is
@@ -24,6 +27,12 @@ type PostBuildSwaggerSchema interface {
 	PostBuildSwaggerSchemaHandler(sm *spec.Schema)
 }
 
+// SchemaMetadataProvider is implemented by types that describe their own validation rules.
+// The entries of the map, typically x- extensions, are added to the extensions of their definition.
+type SchemaMetadataProvider interface {
+	SchemaMetadata() map[string]interface{}
+}
+
 // Check if this structure has a method with signature func (<theModel>) SwaggerDoc() map[string]string
 // If it exists, retrieve the documentation and overwrite all struct tag descriptions
 func getDocFromMethodSwaggerDoc2(model reflect.Type) map[string]string {
@@ -50,6 +59,10 @@ func (b definitionBuilder) addModel(st reflect.Type, nameOverride string) *spec.
 		isArray = true
 		st = st.Elem()
 	}
+	if isAnyType(st) {
+		// there is no schema for this type other than any
+		return nil
+	}
 
 	modelName := keyFrom(st, b.Config)
 	if nameOverride != "" {
@@ -68,10 +81,40 @@ func (b definitionBuilder) addModel(st reflect.Type, nameOverride string) *spec.
 	if b.isByteArrayType(st) {
 		return nil
 	}
+	checkDefinitionType(b.Config, modelName, st)
+	if known, ok := b.knownSchema(st); ok {
+		applyNullableStrategy(b.Config, &known)
+		b.Definitions[modelName] = known
+		return &known
+	}
+	if marshaler := reflect.TypeOf((*json.Marshaler)(nil)).Elem(); st.Implements(marshaler) || reflect.PtrTo(st).Implements(marshaler) {
+		if _, ok := b.Definitions[modelName]; !ok {
+			warnUndocumentedMarshaler(b.Config, st, "type", modelName)
+			b.Definitions[modelName] = spec.Schema{}
+		}
+		sm := b.Definitions[modelName]
+		return &sm
+	}
+	if b.honorTextMarshaler() && isTextMarshaler(st) {
+		if _, ok := b.Definitions[modelName]; !ok {
+			b.Definitions[modelName] = spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"string"}}}
+		}
+		sm := b.Definitions[modelName]
+		return &sm
+	}
 	// see if we already have visited this model
-	if _, ok := b.Definitions[modelName]; ok {
+	if existing, ok := b.Definitions[modelName]; ok {
+		// unless it was left out at the maximum depth and can now be built
+		if !b.isDepthPlaceholder(existing) || b.atMaxDepth() {
+			return nil
+		}
+	}
+	if b.atMaxDepth() {
+		warn(b.Config, "definition is documented as an object, it exceeds the maximum depth", "definition", modelName, "maxDepth", b.Config.MaxDefinitionDepth)
+		b.Definitions[modelName] = depthPlaceholder()
 		return nil
 	}
+	b.depth++
 	sm := spec.Schema{
 		SchemaProps: spec.SchemaProps{
 			Required:   []string{},
@@ -93,11 +136,16 @@ func (b definitionBuilder) addModel(st reflect.Type, nameOverride string) *spec.
 
 	if st.Kind() == reflect.Map {
 		_, sm = b.buildMapType(st, "value", modelName)
+		applyNullableStrategy(b.Config, &sm)
+		setDefinitionMetadata(b, &sm, st)
+		b.postProcessDefinition(modelName, &sm, st)
 		b.Definitions[modelName] = sm
 		return &sm
 	}
 	// check for structure or primitive type
 	if st.Kind() != reflect.Struct {
+		b.postProcessDefinition(modelName, &sm, st)
+		b.Definitions[modelName] = sm
 		return &sm
 	}
 
@@ -106,6 +154,10 @@ func (b definitionBuilder) addModel(st reflect.Type, nameOverride string) *spec.
 
 	for i := 0; i < st.NumField(); i++ {
 		field := st.Field(i)
+		if b.omitUnexportedFields() && !field.IsExported() && !isEmbeddedStruct(field) {
+			// encoding/json ignores these, except the fields of embedded structs
+			continue
+		}
 		jsonName, modelDescription, prop := b.buildProperty(field, &sm, modelName)
 		if len(modelDescription) > 0 {
 			modelDescriptions = append(modelDescriptions, modelDescription)
@@ -125,6 +177,8 @@ func (b definitionBuilder) addModel(st reflect.Type, nameOverride string) *spec.
 		}
 	}
 	sort.Strings(sm.Required)
+	sortProperties(b, &sm, st)
+	applyNullableStrategy(b.Config, &sm)
 	// We always overwrite documentation if SwaggerDoc method exists
 	// "" is special for documenting the struct itself
 	if modelDoc, ok := fullDoc[""]; ok {
@@ -137,10 +191,13 @@ func (b definitionBuilder) addModel(st reflect.Type, nameOverride string) *spec.
 	// See https://github.com/go-openapi/spec/issues/23 for more context
 	sm.ID = ""
 
+	setDefinitionMetadata(b, &sm, st)
+
 	// Call handler to update sch
 	if handler, ok := reflect.New(st).Elem().Interface().(PostBuildSwaggerSchema); ok {
 		handler.PostBuildSwaggerSchemaHandler(&sm)
 	}
+	b.postProcessDefinition(modelName, &sm, st)
 
 	// update model builder with completed model
 	b.Definitions[modelName] = sm
@@ -148,6 +205,28 @@ func (b definitionBuilder) addModel(st reflect.Type, nameOverride string) *spec.
 	return &sm
 }
 
+// atMaxDepth returns true if the definitions being built are nested MaxDefinitionDepth deep.
+func (b definitionBuilder) atMaxDepth() bool {
+	return b.Config.MaxDefinitionDepth > 0 && b.depth >= b.Config.MaxDefinitionDepth
+}
+
+// depthPlaceholder returns the schema of a model that is not built because it exceeds the maximum depth.
+func depthPlaceholder() spec.Schema {
+	return spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"object"}}}
+}
+
+func (b definitionBuilder) isDepthPlaceholder(s spec.Schema) bool {
+	return b.Config.MaxDefinitionDepth > 0 && reflect.DeepEqual(s, depthPlaceholder())
+}
+
+// postProcessDefinition calls the DefinitionPostProcessor of the config, if any.
+func (b definitionBuilder) postProcessDefinition(modelName string, sm *spec.Schema, st reflect.Type) {
+	setConditionalSchema(b, sm, st)
+	if b.Config.DefinitionPostProcessor != nil {
+		b.Config.DefinitionPostProcessor(modelName, sm, st)
+	}
+}
+
 func (b definitionBuilder) isPropertyRequired(field reflect.StructField) bool {
 	required := true
 	if optionalTag := field.Tag.Get("optional"); optionalTag == "true" {
@@ -184,19 +263,40 @@ func (b definitionBuilder) buildProperty(field reflect.StructField, model *spec.
 	}
 	fieldType := field.Type
 
+	if known, ok := b.knownSchema(fieldType); ok {
+		prop = known
+		setPropertyMetadata(b, &prop, field)
+		return jsonName, modelDescription, prop
+	}
+
 	// check if type is doing its own marshalling
 	marshalerType := reflect.TypeOf((*json.Marshaler)(nil)).Elem()
 	if fieldType.Implements(marshalerType) {
+		format := b.jsonSchemaFormat(keyFrom(fieldType, b.Config), fieldType.Kind())
+		if format == "" {
+			// the JSON can be anything, its fields do not tell
+			warnUndocumentedMarshaler(b.Config, fieldType, "type", keyFrom(fieldType, b.Config), "field", field.Name)
+			return jsonName, modelDescription, prop
+		}
 		var pType = "string"
 		if prop.Type == nil {
 			prop.Type = []string{pType}
 		}
 		if prop.Format == "" {
-			prop.Format = b.jsonSchemaFormat(keyFrom(fieldType, b.Config), fieldType.Kind())
+			prop.Format = format
+		}
+		if fieldType.Kind() == reflect.Ptr {
+			setNullable(b, &prop)
 		}
 		return jsonName, modelDescription, prop
 	}
 
+	// encoding/json encodes text marshalers as strings, their fields are internal
+	if b.honorTextMarshaler() && isTextMarshaler(fieldType) {
+		prop.Type = []string{"string"}
+		return jsonName, modelDescription, prop
+	}
+
 	// check if annotation says it is a string
 	if jsonTag := field.Tag.Get("json"); jsonTag != "" {
 		s := strings.Split(jsonTag, ",")
@@ -207,6 +307,12 @@ func (b definitionBuilder) buildProperty(field reflect.StructField, model *spec.
 		}
 	}
 
+	if fieldType.Kind() == reflect.Interface {
+		prop = b.interfaceSchema(fieldType)
+		setPropertyMetadata(b, &prop, field)
+		return jsonName, modelDescription, prop
+	}
+
 	fieldKind := fieldType.Kind()
 	switch {
 	case fieldKind == reflect.Struct:
@@ -228,6 +334,7 @@ func (b definitionBuilder) buildProperty(field reflect.StructField, model *spec.
 		mapped := b.jsonSchemaType(fieldTypeName, fieldKind)
 		prop.Type = []string{mapped}
 		prop.Format = b.jsonSchemaFormat(fieldTypeName, fieldKind)
+		setTypeEnumValues(b, &prop, fieldType)
 		return jsonName, modelDescription, prop
 	}
 	modelType := keyFrom(fieldType, b.Config)
@@ -270,7 +377,8 @@ func (b definitionBuilder) buildStructTypeProperty(field reflect.StructField, js
 
 	if field.Name == fieldType.Name() && field.Anonymous && !hasNamedJSONTag(field) {
 		// embedded struct
-		sub := definitionBuilder{b.Definitions, b.Config}
+		// the embedded model is at the same depth
+		sub := definitionBuilder{Definitions: b.Definitions, Config: b.Config, depth: b.depth - 1}
 		sub.addModel(fieldType, "")
 		subKey := keyFrom(fieldType, b.Config)
 		// merge properties from sub
@@ -316,6 +424,14 @@ func (b definitionBuilder) buildArrayTypeProperty(field reflect.StructField, jso
 		prop.Type = []string{stringt}
 		return jsonName, prop
 	}
+	if b.isByteArrayType(fieldType.Elem()) && fieldType.Elem().Kind() == reflect.Slice {
+		// each []byte encodes as a base64-encoded string, e.g. proto3 repeated bytes
+		prop.Type = []string{"array"}
+		prop.Items = &spec.SchemaOrArray{
+			Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"string"}, Format: "byte"}},
+		}
+		return jsonName, prop
+	}
 	var pType = "array"
 	prop.Type = []string{pType}
 	isPrimitive := b.isPrimitiveType(fieldType.Elem().Name(), fieldType.Elem().Kind())
@@ -323,10 +439,16 @@ func (b definitionBuilder) buildArrayTypeProperty(field reflect.StructField, jso
 	prop.Items = &spec.SchemaOrArray{
 		Schema: &spec.Schema{},
 	}
+	if fieldType.Elem().Kind() == reflect.Interface {
+		*prop.Items.Schema = b.interfaceSchema(fieldType.Elem())
+		return jsonName, prop
+	}
 	if isPrimitive {
 		mapped := b.jsonSchemaType(elemTypeName, fieldType.Elem().Kind())
 		prop.Items.Schema.Type = []string{mapped}
 		prop.Items.Schema.Format = b.jsonSchemaFormat(elemTypeName, fieldType.Elem().Kind())
+		prop.Items.Schema.Enum = enumValuesOf(fieldType.Elem(), b.Config)
+		setXMLItemsName(prop.Items.Schema, field)
 	} else {
 		prop.Items.Schema.Ref = spec.MustCreateRef("#/definitions/" + elemTypeName)
 	}
@@ -383,6 +505,7 @@ func (b definitionBuilder) buildMapType(mapType reflect.Type, jsonName, modelNam
 			} else if isPrimitive {
 				mapped := b.jsonSchemaType(elemTypeName, mapType.Elem().Kind())
 				prop.AdditionalProperties.Schema.Type = []string{mapped}
+				prop.AdditionalProperties.Schema.Format = b.jsonSchemaFormat(elemTypeName, mapType.Elem().Kind())
 			} else {
 				prop.AdditionalProperties.Schema.Ref = spec.MustCreateRef("#/definitions/" + elemTypeName)
 			}
@@ -420,6 +543,9 @@ func (b definitionBuilder) buildPointerTypeProperty(field reflect.StructField, j
 			// add|overwrite model for element type
 			b.addModel(fieldType.Elem().Elem(), elemName)
 		}
+	} else if fieldType.Elem().Kind() == reflect.Interface {
+		prop = b.interfaceSchema(fieldType.Elem())
+		setPropertyMetadata(b, &prop, field)
 	} else {
 		// non-array, pointer type
 		fieldTypeName := keyFrom(fieldType.Elem(), b.Config)
@@ -428,6 +554,8 @@ func (b definitionBuilder) buildPointerTypeProperty(field reflect.StructField, j
 		if isPrimitive {
 			prop.Type = []string{pType}
 			prop.Format = b.jsonSchemaFormat(fieldTypeName, fieldType.Elem().Kind())
+			setTypeEnumValues(b, &prop, fieldType.Elem())
+			setNullable(b, &prop)
 			return jsonName, prop
 		}
 		prop.Ref = spec.MustCreateRef("#/definitions/" + pType)
@@ -443,6 +571,70 @@ func (b definitionBuilder) buildPointerTypeProperty(field reflect.StructField, j
 	return jsonName, prop
 }
 
+// setNullable marks the property of a pointer as nullable, unless its x-nullable tag already has.
+// The x-nullable extension is replaced according to the NullableStrategy once the definition is built,
+// see applyNullableStrategy.
+func setNullable(b definitionBuilder, prop *spec.Schema) {
+	if _, ok := prop.Extensions["x-nullable"]; ok {
+		return
+	}
+	prop.AddExtension("x-nullable", true)
+}
+
+// nullableStrategy returns the NullableStrategy of the config, which is NullableKeyword by default for OpenAPI 3.
+// A strategy that the OpenAPIVersion cannot represent falls back: to NullableExtension for Swagger 2.0,
+// which has neither the nullable keyword nor oneOf, and NullableOneOf to NullableKeyword for OpenAPI 3.0.
+func nullableStrategy(config Config) NullableStrategy {
+	switch {
+	case !isOpenAPI3(config):
+		return NullableExtension
+	case config.NullableStrategy == NullableExtension:
+		return NullableKeyword
+	case config.NullableStrategy == NullableOneOf && config.OpenAPIVersion != OpenAPIVersion31:
+		return NullableKeyword
+	}
+	return config.NullableStrategy
+}
+
+// applyNullableStrategy replaces the x-nullable extension of the schema and its inline subschemas
+// by the representation of the NullableStrategy of the config.
+func applyNullableStrategy(config Config, s *spec.Schema) {
+	for name, prop := range s.Properties {
+		applyNullableStrategy(config, &prop)
+		s.Properties[name] = prop
+	}
+	if s.Items != nil && s.Items.Schema != nil {
+		applyNullableStrategy(config, s.Items.Schema)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		applyNullableStrategy(config, s.AdditionalProperties.Schema)
+	}
+	strategy := nullableStrategy(config)
+	nullable, ok := s.Extensions["x-nullable"].(bool)
+	if !ok || strategy == NullableExtension {
+		return
+	}
+	delete(s.Extensions, "x-nullable")
+	if len(s.Extensions) == 0 {
+		s.Extensions = nil
+	}
+	if !nullable {
+		return
+	}
+	if strategy == NullableKeyword {
+		s.Nullable = true
+		return
+	}
+	// the documentation stays with the property, the value is either the schema or null
+	inner := *s
+	inner.Title, inner.Description = "", ""
+	*s = spec.Schema{SchemaProps: spec.SchemaProps{
+		Title:       s.Title,
+		Description: s.Description,
+		OneOf:       []spec.Schema{inner, {SchemaProps: spec.SchemaProps{Type: []string{"null"}}}},
+	}}
+}
+
 func (b definitionBuilder) getElementTypeName(modelName, jsonName string, t reflect.Type) string {
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -469,6 +661,105 @@ func keyFrom(st reflect.Type, cfg Config) string {
 	return key
 }
 
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// isTextMarshaler returns true if the type, or a pointer to it, implements encoding.TextMarshaler
+// and does not implement json.Marshaler, which encoding/json uses first.
+func isTextMarshaler(t reflect.Type) bool {
+	marshalerType := reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	implements := func(i reflect.Type) bool {
+		return t.Implements(i) || (t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(i))
+	}
+	return implements(textMarshalerType) && !implements(marshalerType)
+}
+
+// knownSchema returns the schema of a type, or the type it points to, that is not derived from its fields:
+// that of the JSONMarshalerOverrides, the DecimalTypes or a nullable type.
+func (b definitionBuilder) knownSchema(t reflect.Type) (spec.Schema, bool) {
+	if override, ok := b.jsonMarshalerOverride(t); ok {
+		return override, true
+	}
+	if decimal, ok := b.decimalSchema(t); ok {
+		return decimal, true
+	}
+	return b.nullablePrimitive(t)
+}
+
+// decimalSchema returns the schema of the type if it, or the type it points to, is one of the DecimalTypes.
+func (b definitionBuilder) decimalSchema(t reflect.Type) (spec.Schema, bool) {
+	for _, each := range b.Config.DecimalTypes {
+		if each == t || (t.Kind() == reflect.Ptr && each == t.Elem()) {
+			jsonType := "number"
+			if b.Config.DecimalFormat == DecimalFormatString {
+				jsonType = "string"
+			}
+			return spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{jsonType}, Format: "decimal"}}, true
+		}
+	}
+	return spec.Schema{}, false
+}
+
+// jsonMarshalerOverride returns a copy of the schema in Config.JSONMarshalerOverrides for the type, or the type it points to.
+func (b definitionBuilder) jsonMarshalerOverride(t reflect.Type) (spec.Schema, bool) {
+	override, ok := b.Config.JSONMarshalerOverrides[t]
+	if !ok && t.Kind() == reflect.Ptr {
+		override, ok = b.Config.JSONMarshalerOverrides[t.Elem()]
+	}
+	if !ok || override == nil {
+		return spec.Schema{}, false
+	}
+	clone, err := cloneSchema(*override)
+	if err != nil {
+		return *override, true
+	}
+	return clone, true
+}
+
+// omitUnexportedFields returns true unless Config.OmitUnexportedFields is set to false.
+func (b definitionBuilder) omitUnexportedFields() bool {
+	return b.Config.OmitUnexportedFields == nil || *b.Config.OmitUnexportedFields
+}
+
+// isEmbeddedStruct returns true if the field is an embedded struct or pointer to a struct.
+func isEmbeddedStruct(field reflect.StructField) bool {
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return field.Anonymous && t.Kind() == reflect.Struct
+}
+
+// honorTextMarshaler returns true unless Config.HonorTextMarshaler is set to false.
+func (b definitionBuilder) honorTextMarshaler() bool {
+	return b.Config.HonorTextMarshaler == nil || *b.Config.HonorTextMarshaler
+}
+
+// isAnyType returns true if the type has no JSON representation that can be described by a definition,
+// such as interfaces and functions.
+func isAnyType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return true
+	}
+	return false
+}
+
+// interfaceSchema returns a copy of the schema of the InterfaceSchemaFunc for the interface type
+// or, if there is none, the empty schema: the value can be anything.
+func (b definitionBuilder) interfaceSchema(t reflect.Type) spec.Schema {
+	if b.Config.InterfaceSchemaFunc == nil {
+		return spec.Schema{}
+	}
+	s := b.Config.InterfaceSchemaFunc(t)
+	if s == nil {
+		return spec.Schema{}
+	}
+	if clone, err := cloneSchema(*s); err == nil {
+		return clone
+	}
+	return *s
+}
+
 func (b definitionBuilder) isSliceOrArrayType(t reflect.Kind) bool {
 	return t == reflect.Slice || t == reflect.Array
 }
@@ -502,6 +793,15 @@ func (b definitionBuilder) isPrimitiveType(modelName string, modelKind reflect.K
 // jsonNameOfField returns the name of the field as it should appear in JSON format
 // An empty string indicates that this field is not part of the JSON representation
 func (b definitionBuilder) jsonNameOfField(field reflect.StructField) string {
+	name := b.taggedNameOfField(field)
+	if name == "" || b.Config.PropertyNameNormalizer == nil {
+		return name
+	}
+	return b.Config.PropertyNameNormalizer(name)
+}
+
+// taggedNameOfField returns the name of the field from its json tag or else the DefinitionNameHandler.
+func (b definitionBuilder) taggedNameOfField(field reflect.StructField) string {
 	if jsonTag := field.Tag.Get("json"); jsonTag != "" {
 		s := strings.Split(jsonTag, ",")
 		if s[0] == "-" {
@@ -589,9 +889,9 @@ func (b definitionBuilder) jsonSchemaFormat(modelName string, modelKind reflect.
 	case reflect.Uint16:
 		return "integer"
 	case reflect.Uint32:
-		return "integer"
+		return "int32"
 	case reflect.Uint64:
-		return "integer"
+		return "int64"
 	}
 
 	return "" // no format
@@ -1,9 +1,17 @@
 package restfulspec
 
 import (
+	"database/sql"
 	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	restful "github.com/emicklei/go-restful/v3"
 	"github.com/go-openapi/spec"
 )
 
@@ -393,7 +401,8 @@ type (
 )
 
 func TestPotentialStackOverflow(t *testing.T) {
-	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	omit := false
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{OmitUnexportedFields: &omit}}
 	db.addModelFrom(X{})
 
 	if got, want := len(db.Definitions), 2; got != want {
@@ -427,7 +436,8 @@ type Bar struct {
 }
 
 func TestRecursiveFieldStructure(t *testing.T) {
-	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	omit := false
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{OmitUnexportedFields: &omit}}
 	db.addModelFrom(Foo{})
 	t.Log(db)
 }
@@ -440,14 +450,17 @@ type email struct {
 func TestDoubleByteArray(t *testing.T) {
 	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
 	db.addModelFrom(email{})
-	sc, ok := db.Definitions["restfulspec.email.attachments"]
-	if !ok {
-		t.Fail()
+	if _, ok := db.Definitions["restfulspec.email.attachments"]; ok {
+		t.Errorf("unexpected definition for [][]byte field: %v", db.Definitions)
 	}
+	sc := db.Definitions["restfulspec.email"].Properties["attachments"]
 	t.Log(sc)
 	if got, want := sc.Type[0], "array"; got != want {
 		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
 	}
+	if got, want := sc.Items.Schema.Type[0]+" "+sc.Items.Schema.Format, "string byte"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
 }
 
 type matrix struct {
@@ -515,3 +528,693 @@ func TestPostBuildSwaggerSchema(t *testing.T) {
 	}
 	t.Log(sc.Description)
 }
+
+func TestDefinitionPostProcessor(t *testing.T) {
+	types := map[string]reflect.Type{}
+	cfg := Config{
+		DefinitionPostProcessor: func(name string, schema *spec.Schema, st reflect.Type) {
+			types[name] = st
+			schema.AddExtension("x-processed", true)
+		},
+	}
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: cfg}
+	db.addModelFrom(Sample{})
+	db.addModelFrom(map[string]Item{})
+
+	for name, st := range map[string]reflect.Type{
+		"restfulspec.Sample":          reflect.TypeOf(Sample{}),
+		"restfulspec.Item":            reflect.TypeOf(Item{}),
+		"map[string]restfulspec.Item": reflect.TypeOf(map[string]Item{}),
+	} {
+		if got, want := types[name], st; got != want {
+			t.Errorf("%s: got %v want %v", name, got, want)
+		}
+		if got, want := db.Definitions[name].Extensions["x-processed"], true; got != want {
+			t.Errorf("%s: got %v want %v", name, got, want)
+		}
+	}
+}
+
+type Level1 struct {
+	Next *Level2
+}
+
+type Level2 struct {
+	Embedded
+	Next []Level3
+}
+
+type Embedded struct {
+	Note string
+}
+
+type Level3 struct {
+	Name string
+}
+
+func TestMaxDefinitionDepth(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{MaxDefinitionDepth: 2}}
+	db.addModelFrom(Level1{})
+
+	if _, ok := db.Definitions["restfulspec.Level2"].Properties["Note"]; !ok {
+		t.Errorf("embedded struct at the maximum depth was not merged")
+	}
+	if got, want := db.Definitions["restfulspec.Level3"], depthPlaceholder(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	// reached at a lower depth
+	db.addModelFrom(Level3{})
+	if _, ok := db.Definitions["restfulspec.Level3"].Properties["Name"]; !ok {
+		t.Errorf("placeholder was not replaced")
+	}
+
+	db = definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Level1{})
+	if _, ok := db.Definitions["restfulspec.Level3"].Properties["Name"]; !ok {
+		t.Errorf("depth is limited by default")
+	}
+}
+
+// ProtoLabel and ProtoCatalog are shaped like the structs that protoc-gen-go generates.
+type ProtoLabel struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+type ProtoCatalog struct {
+	Labels     map[string]*ProtoLabel `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	LabelsByID map[int64]*ProtoLabel  `protobuf:"bytes,2,rep,name=labels_by_id,json=labelsById,proto3" json:"labels_by_id,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Counts     map[string]int32       `protobuf:"bytes,3,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func TestProto3MapFields(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(ProtoCatalog{})
+
+	if _, ok := db.Definitions["restfulspec.ProtoLabel"]; !ok {
+		t.Fatal("missing definition of the map value message")
+	}
+	schema := db.Definitions["restfulspec.ProtoCatalog"]
+	for _, name := range []string{"labels", "labels_by_id"} {
+		prop := schema.Properties[name]
+		if got, want := prop.Type[0], "object"; got != want {
+			t.Errorf("%s: got %v want %v", name, got, want)
+		}
+		if got, want := prop.AdditionalProperties.Schema.Ref.String(), "#/definitions/restfulspec.ProtoLabel"; got != want {
+			t.Errorf("%s: got %v want %v", name, got, want)
+		}
+	}
+	counts := schema.Properties["counts"].AdditionalProperties.Schema
+	if got, want := counts.Type[0]+" "+counts.Format, "integer int32"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := len(schema.Required), 0; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+type ProtoScalars struct {
+	Int32s   []int32   `protobuf:"varint,1,rep,packed,name=int32s,proto3" json:"int32s,omitempty"`
+	Int64s   []int64   `protobuf:"varint,2,rep,packed,name=int64s,proto3" json:"int64s,omitempty"`
+	Uint32s  []uint32  `protobuf:"varint,3,rep,packed,name=uint32s,proto3" json:"uint32s,omitempty"`
+	Uint64s  []uint64  `protobuf:"varint,4,rep,packed,name=uint64s,proto3" json:"uint64s,omitempty"`
+	Sint32s  []int32   `protobuf:"zigzag32,5,rep,packed,name=sint32s,proto3" json:"sint32s,omitempty"`
+	Fixed64s []uint64  `protobuf:"fixed64,6,rep,packed,name=fixed64s,proto3" json:"fixed64s,omitempty"`
+	Floats   []float32 `protobuf:"fixed32,7,rep,packed,name=floats,proto3" json:"floats,omitempty"`
+	Doubles  []float64 `protobuf:"fixed64,8,rep,packed,name=doubles,proto3" json:"doubles,omitempty"`
+	Bools    []bool    `protobuf:"varint,9,rep,packed,name=bools,proto3" json:"bools,omitempty"`
+	Strings  []string  `protobuf:"bytes,10,rep,name=strings,proto3" json:"strings,omitempty"`
+	Blobs    [][]byte  `protobuf:"bytes,11,rep,name=blobs,proto3" json:"blobs,omitempty"`
+	Uint32   uint32    `protobuf:"varint,12,opt,name=uint32,proto3" json:"uint32,omitempty"`
+}
+
+func TestProto3RepeatedScalarFields(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(ProtoScalars{})
+
+	schema := db.Definitions["restfulspec.ProtoScalars"]
+	for name, want := range map[string]string{
+		"int32s":   "integer int32",
+		"int64s":   "integer int64",
+		"uint32s":  "integer int32",
+		"uint64s":  "integer int64",
+		"sint32s":  "integer int32",
+		"fixed64s": "integer int64",
+		"floats":   "number float",
+		"doubles":  "number double",
+		"bools":    "boolean ",
+		"strings":  "string ",
+	} {
+		prop := schema.Properties[name]
+		if got := prop.Type[0]; got != "array" {
+			t.Errorf("%s: got %v want array", name, got)
+			continue
+		}
+		items := prop.Items.Schema
+		if got := items.Type[0] + " " + items.Format; got != want {
+			t.Errorf("%s: got %v want %v", name, got, want)
+		}
+	}
+	if got, want := schema.Properties["uint32"].Format, "int32"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	if len(db.Definitions) != 1 {
+		t.Errorf("got definitions %v want only restfulspec.ProtoScalars", db.Definitions)
+	}
+	blobs := schema.Properties["blobs"]
+	if got, want := blobs.Type[0]+" of "+blobs.Items.Schema.Type[0]+" "+blobs.Items.Schema.Format, "array of string byte"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+type Version struct {
+	major, minor int
+}
+
+func (v *Version) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%d", v.major, v.minor)), nil
+}
+
+type Release struct {
+	Version  Version
+	Previous *Version
+	History  []Version
+	Size     *big.Int
+	Address  net.IP
+	Date     time.Time
+}
+
+func TestHonorTextMarshaler(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Release{})
+
+	schema := db.Definitions["restfulspec.Release"]
+	for _, name := range []string{"Version", "Previous", "Address"} {
+		prop := schema.Properties[name]
+		if len(prop.Type) != 1 || prop.Type[0] != "string" || len(prop.Properties) != 0 {
+			t.Errorf("%s: got %s", name, asJSON(prop))
+		}
+	}
+	if got, want := schema.Properties["Date"].Format, "date-time"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	history := schema.Properties["History"]
+	if got, want := history.Items.Schema.Ref.String(), "#/definitions/restfulspec.Version"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := db.Definitions["restfulspec.Version"].Type[0], "string"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	honor := false
+	db = definitionBuilder{Definitions: spec.Definitions{}, Config: Config{HonorTextMarshaler: &honor}}
+	db.addModelFrom(Release{})
+	if ref := db.Definitions["restfulspec.Release"].Properties["Version"].Ref; ref.String() != "#/definitions/restfulspec.Version" {
+		t.Errorf("got %v", ref.String())
+	}
+}
+
+type Amount struct {
+	cents int64
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%d.%02d", a.cents/100, a.cents%100))
+}
+
+type Payment struct {
+	Amount  Amount `description:"amount in euros"`
+	Total   *big.Int
+	Balance Amount
+	Refund  *Amount
+	Fee     json.RawMessage
+}
+
+func TestJSONMarshalerOverrides(t *testing.T) {
+	logger := new(recordingWarnLogger)
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{Logger: logger, diagnostics: newDiagnostics()}}
+	db.addModelFrom(Payment{})
+	db.addModelFrom(Amount{})
+	schema := db.Definitions["restfulspec.Payment"]
+	for _, name := range []string{"Amount", "Total", "Fee"} {
+		if prop := schema.Properties[name]; len(prop.Type) != 0 || len(prop.Properties) != 0 {
+			t.Errorf("%s: got %s", name, asJSON(prop))
+		}
+	}
+	// once for each of Amount, big.Int and json.RawMessage
+	if got, want := len(logger.warnings), 3; got != want {
+		t.Errorf("got %d warnings want %d: %v", got, want, logger.warnings)
+	}
+
+	amount := spec.StringProperty().WithPattern(`^\d+\.\d{2}$`)
+	db = definitionBuilder{Definitions: spec.Definitions{}, Config: Config{JSONMarshalerOverrides: map[reflect.Type]*spec.Schema{
+		reflect.TypeOf(Amount{}):  amount,
+		reflect.TypeOf(big.Int{}): spec.Int64Property(),
+	}}}
+	db.addModelFrom(Payment{})
+	schema = db.Definitions["restfulspec.Payment"]
+	for _, name := range []string{"Amount", "Balance", "Refund"} {
+		if prop := schema.Properties[name]; prop.Type[0] != "string" || prop.Pattern != amount.Pattern {
+			t.Errorf("%s: got %s", name, asJSON(prop))
+		}
+	}
+	if got, want := schema.Properties["Amount"].Description, "amount in euros"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if amount.Description != "" {
+		t.Error("override should not be modified")
+	}
+	if got, want := schema.Properties["Total"].Format, "int64"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	// top-level types
+	db.addModelFrom(Amount{})
+	if got, want := db.Definitions["restfulspec.Amount"].Pattern, amount.Pattern; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+type Customer struct {
+	Name      sql.NullString
+	Age       sql.NullInt32
+	Score     sql.NullFloat64
+	Active    sql.NullBool
+	Visits    sql.NullInt64
+	LastVisit sql.NullTime
+	Nickname  *sql.NullString `x-nullable:"false"`
+	Aliases   []sql.NullString
+}
+
+func TestSQLNullTypes(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Customer{})
+	schema := db.Definitions["restfulspec.Customer"]
+	for name, want := range map[string][]string{
+		"Name":      {"string", ""},
+		"Age":       {"integer", "int32"},
+		"Score":     {"number", "double"},
+		"Active":    {"boolean", ""},
+		"Visits":    {"integer", "int64"},
+		"LastVisit": {"string", "date-time"},
+	} {
+		prop := schema.Properties[name]
+		if prop.Type[0] != want[0] || prop.Format != want[1] || prop.Extensions["x-nullable"] != true {
+			t.Errorf("%s: got %s", name, asJSON(prop))
+		}
+	}
+	if got, want := schema.Properties["Nickname"].Extensions["x-nullable"], false; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := schema.Properties["Aliases"].Items.Schema.Ref.String(), "#/definitions/sql.NullString"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if def := db.Definitions["sql.NullString"]; def.Type[0] != "string" || def.Extensions["x-nullable"] != true {
+		t.Errorf("got %s", asJSON(def))
+	}
+}
+
+// Text and Timestamptz stand in for the types of the pgtype package.
+type Text struct {
+	String string
+	Valid  bool
+}
+
+type Timestamptz struct {
+	Time  time.Time
+	Valid bool
+}
+
+type Account struct {
+	Name    Text
+	Created *Timestamptz
+}
+
+func TestPgtypeMapping(t *testing.T) {
+	pgtypePackages = append(pgtypePackages, reflect.TypeOf(Text{}).PkgPath())
+	defer func() { pgtypePackages = pgtypePackages[:len(pgtypePackages)-1] }()
+
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Account{})
+	ref := db.Definitions["restfulspec.Account"].Properties["Name"].Ref
+	if got, want := ref.String(), "#/definitions/restfulspec.Text"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	db = definitionBuilder{Definitions: spec.Definitions{}, Config: Config{PgtypeMapping: true}}
+	db.addModelFrom(Account{})
+	schema := db.Definitions["restfulspec.Account"]
+	for name, want := range map[string]string{"Name": "", "Created": "date-time"} {
+		prop := schema.Properties[name]
+		if prop.Type[0] != "string" || prop.Format != want || prop.Extensions["x-nullable"] != true {
+			t.Errorf("%s: got %s", name, asJSON(prop))
+		}
+	}
+	if _, ok := db.Definitions["restfulspec.Text"]; ok {
+		t.Error("unexpected definition of pgtype type")
+	}
+	if ext := pgtypeSchemas["Text"].Extensions; ext != nil {
+		t.Errorf("got %v", ext)
+	}
+}
+
+// Decimal stands in for a decimal library type that encodes as a JSON string.
+type Decimal struct {
+	value *big.Int
+	exp   int32
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%se%d", d.value, d.exp))
+}
+
+type Quote struct {
+	Total    Decimal
+	Discount *Decimal
+	Lines    []Decimal
+}
+
+func TestDecimalTypes(t *testing.T) {
+	for format, want := range map[string]string{"": "number", DecimalFormatNumber: "number", DecimalFormatString: "string"} {
+		db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{
+			DecimalTypes:  []reflect.Type{reflect.TypeOf(Decimal{})},
+			DecimalFormat: format,
+		}}
+		db.addModelFrom(Quote{})
+		schema := db.Definitions["restfulspec.Quote"]
+		for _, each := range []spec.Schema{schema.Properties["Total"], schema.Properties["Discount"], db.Definitions["restfulspec.Decimal"]} {
+			if each.Type[0] != want || each.Format != "decimal" {
+				t.Errorf("%q: got %s", format, asJSON(each))
+			}
+		}
+	}
+	if _, err := BuildSwaggerWithError(Config{DecimalFormat: "float"}); err == nil {
+		t.Error("expected error for unknown DecimalFormat")
+	}
+}
+
+type Optionals struct {
+	Bool     *bool
+	Int      *int
+	Int8     *int8
+	Int16    *int16
+	Int32    *int32
+	Int64    *int64
+	Uint     *uint
+	Uint8    *uint8
+	Uint16   *uint16
+	Uint32   *uint32
+	Uint64   *uint64
+	Float32  *float32
+	Float64  *float64
+	String   *string
+	Alias    *StringAlias
+	Time     *time.Time
+	Duration *time.Duration
+	Number   *json.Number
+	Required *string `x-nullable:"false"`
+	Plain    string
+}
+
+func TestPointerToPrimitiveIsNullable(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Optionals{})
+	schema := db.Definitions["restfulspec.Optionals"]
+	for name, want := range map[string][]string{
+		"Bool":     {"boolean", ""},
+		"Int":      {"integer", "int32"},
+		"Int8":     {"integer", "byte"},
+		"Int16":    {"integer", "integer"},
+		"Int32":    {"integer", "int32"},
+		"Int64":    {"integer", "int64"},
+		"Uint":     {"integer", "integer"},
+		"Uint8":    {"integer", "byte"},
+		"Uint16":   {"integer", "integer"},
+		"Uint32":   {"integer", "int32"},
+		"Uint64":   {"integer", "int64"},
+		"Float32":  {"number", "float"},
+		"Float64":  {"number", "double"},
+		"String":   {"string", ""},
+		"Alias":    {"string", ""},
+		"Time":     {"string", "date-time"},
+		"Duration": {"integer", "int64"},
+		"Number":   {"number", "double"},
+	} {
+		prop := schema.Properties[name]
+		if len(prop.Type) != 1 || prop.Type[0] != want[0] || prop.Format != want[1] || prop.Extensions["x-nullable"] != true {
+			t.Errorf("%s: got %s", name, asJSON(prop))
+		}
+	}
+	if got, want := schema.Properties["Required"].Extensions["x-nullable"], false; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if _, ok := schema.Properties["Plain"].Extensions["x-nullable"]; ok {
+		t.Error("non-pointer should not be nullable")
+	}
+}
+
+type Envelope struct {
+	Payload  interface{}
+	Metadata any `description:"anything"`
+	Optional *interface{}
+	Items    []interface{}
+	Headers  map[string]any
+}
+
+func TestEmptyInterfaceFields(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Envelope{})
+	if got, want := len(db.Definitions), 1; got != want {
+		t.Fatalf("got %d definitions want %d: %s", got, want, asJSON(db.Definitions))
+	}
+	schema := db.Definitions["restfulspec.Envelope"]
+	for _, name := range []string{"Payload", "Metadata", "Optional"} {
+		prop := schema.Properties[name]
+		if len(prop.Type) != 0 || prop.Ref.String() != "" {
+			t.Errorf("%s: got %s", name, asJSON(prop))
+		}
+	}
+	if got, want := schema.Properties["Metadata"].Description, "anything"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	items := schema.Properties["Items"].Items.Schema
+	if len(items.Type) != 0 || items.Ref.String() != "" {
+		t.Errorf("got %s", asJSON(items))
+	}
+}
+
+type Shape interface {
+	Area() float64
+}
+
+type Drawing struct {
+	Main    Shape
+	Shapes  []Shape
+	Overlay *Shape
+	Notes   interface{}
+}
+
+func TestInterfaceSchemaFunc(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Drawing{})
+	if got, want := asJSON(db.Definitions["restfulspec.Drawing"].Properties["Main"]), "{}"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	shape := spec.RefSchema("#/definitions/ShapeBase")
+	var called []reflect.Type
+	db = definitionBuilder{Definitions: spec.Definitions{}, Config: Config{InterfaceSchemaFunc: func(t reflect.Type) *spec.Schema {
+		called = append(called, t)
+		if t == reflect.TypeOf((*Shape)(nil)).Elem() {
+			return shape
+		}
+		return nil
+	}}}
+	db.addModelFrom(Drawing{})
+	schema := db.Definitions["restfulspec.Drawing"]
+	for _, each := range []spec.Schema{schema.Properties["Main"], *schema.Properties["Shapes"].Items.Schema, schema.Properties["Overlay"]} {
+		if got, want := each.Ref.String(), "#/definitions/ShapeBase"; got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+	}
+	if got, want := asJSON(schema.Properties["Notes"]), "{}"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := len(called), 4; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+type UserID = string
+
+type AppleAlias = Apple
+
+type Member struct {
+	ID       UserID
+	Managers []UserID
+	Favorite AppleAlias
+}
+
+// Aliases are the same type as the type they denote, reflect cannot tell them apart.
+func TestTypeAliases(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Member{})
+	schema := db.Definitions["restfulspec.Member"]
+	if got, want := schema.Properties["ID"].Type[0], "string"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := schema.Properties["Managers"].Items.Schema.Type[0], "string"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	ref := schema.Properties["Favorite"].Ref
+	if got, want := ref.String(), "#/definitions/restfulspec.Apple"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := len(db.Definitions), 2; got != want {
+		t.Errorf("got %d definitions want %d: %v", got, want, asJSON(db.Definitions))
+	}
+}
+
+type Login struct {
+	User   string
+	secret string
+	audit
+}
+
+type audit struct {
+	Created time.Time
+	by      string
+}
+
+func TestOmitUnexportedFields(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Login{})
+	props := db.Definitions["restfulspec.Login"].Properties
+	if got, want := len(props), 2; got != want {
+		t.Errorf("got %d properties want %d: %v", got, want, asJSON(props))
+	}
+	for _, name := range []string{"User", "Created"} {
+		if _, ok := props[name]; !ok {
+			t.Errorf("missing %s", name)
+		}
+	}
+
+	omit := false
+	db = definitionBuilder{Definitions: spec.Definitions{}, Config: Config{OmitUnexportedFields: &omit}}
+	db.addModelFrom(Login{})
+	props = db.Definitions["restfulspec.Login"].Properties
+	for _, name := range []string{"User", "secret", "Created", "by"} {
+		if _, ok := props[name]; !ok {
+			t.Errorf("missing %s in %v", name, asJSON(props))
+		}
+	}
+}
+
+type Profile struct {
+	Nickname *string
+	Birthday *time.Time
+	Phone    sql.NullString
+}
+
+func TestNullableOpenAPIVersions(t *testing.T) {
+	for _, version := range []string{"", OpenAPIVersion2, OpenAPIVersion3, OpenAPIVersion31} {
+		db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{OpenAPIVersion: version}}
+		db.addModelFrom(Profile{})
+		props := db.Definitions["restfulspec.Profile"].Properties
+		oas3 := version == OpenAPIVersion3 || version == OpenAPIVersion31
+		for _, name := range []string{"Nickname", "Birthday", "Phone"} {
+			prop := props[name]
+			_, extension := prop.Extensions["x-nullable"]
+			if prop.Nullable != oas3 || extension == oas3 {
+				t.Errorf("%q %s: got %s", version, name, asJSON(prop))
+			}
+		}
+	}
+	if _, err := BuildSwaggerWithError(Config{OpenAPIVersion: "3"}); err == nil {
+		t.Error("expected error for unknown OpenAPIVersion")
+	}
+}
+
+type Settings struct {
+	Theme   *string `description:"color theme"`
+	Locale  string  `x-nullable:"true"`
+	Retries *int
+}
+
+func TestNullableStrategy(t *testing.T) {
+	build := func(strategy NullableStrategy, version string) spec.SchemaProperties {
+		db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{NullableStrategy: strategy, OpenAPIVersion: version}}
+		db.addModelFrom(Settings{})
+		return db.Definitions["restfulspec.Settings"].Properties
+	}
+
+	props := build(NullableExtension, "")
+	for _, each := range []spec.Schema{props["Theme"], props["Locale"], props["Retries"]} {
+		if got, want := each.Extensions["x-nullable"], true; got != want || each.Nullable {
+			t.Errorf("extension: got %s", asJSON(each))
+		}
+	}
+
+	props = build(NullableKeyword, OpenAPIVersion3)
+	for _, each := range []spec.Schema{props["Theme"], props["Locale"], props["Retries"]} {
+		if _, ok := each.Extensions["x-nullable"]; ok || !each.Nullable {
+			t.Errorf("keyword: got %s", asJSON(each))
+		}
+	}
+
+	props = build(NullableOneOf, OpenAPIVersion31)
+	theme := props["Theme"]
+	if got, want := theme.Description, "color theme"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := len(theme.OneOf), 2; got != want {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	if got, want := theme.OneOf[0].Type[0]+"/"+theme.OneOf[1].Type[0], "string/null"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if theme.OneOf[0].Description != "" || theme.OneOf[0].Extensions != nil {
+		t.Errorf("got %s", asJSON(theme.OneOf[0]))
+	}
+	if got, want := len(props["Locale"].OneOf), 2; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	if _, err := BuildSwaggerWithError(Config{NullableStrategy: NullableOneOf + 1}); err == nil {
+		t.Error("expected error for unknown NullableStrategy")
+	}
+	for _, version := range []string{"", OpenAPIVersion2, OpenAPIVersion3} {
+		if _, err := BuildSwaggerWithError(Config{NullableStrategy: NullableOneOf, OpenAPIVersion: version}); err == nil {
+			t.Errorf("%q: expected error for NullableOneOf", version)
+		}
+	}
+	if _, err := BuildSwaggerWithError(Config{NullableStrategy: NullableKeyword}); err == nil {
+		t.Error("expected error for NullableKeyword in Swagger 2.0")
+	}
+	if _, err := BuildSwaggerWithError(Config{NullableStrategy: NullableOneOf, OpenAPIVersion: OpenAPIVersion31}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNullableStrategyFallsBack(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/settings").To(dummy).Returns(200, "ok", Settings{}))
+	for _, strategy := range []NullableStrategy{NullableKeyword, NullableOneOf} {
+		data, err := json.Marshal(BuildSwagger(Config{WebServices: []*restful.WebService{ws}, NullableStrategy: strategy, Logger: NopLogger{}}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc := string(data)
+		if strings.Contains(doc, "oneOf") || strings.Contains(doc, `"nullable"`) || !strings.Contains(doc, `"x-nullable":true`) {
+			t.Errorf("%d: got %s want x-nullable of Swagger 2.0", strategy, doc)
+		}
+	}
+
+	// OpenAPI 3.0 has no null type
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{NullableStrategy: NullableOneOf, OpenAPIVersion: OpenAPIVersion3}}
+	db.addModelFrom(Settings{})
+	if theme := db.Definitions["restfulspec.Settings"].Properties["Theme"]; len(theme.OneOf) != 0 || !theme.Nullable {
+		t.Errorf("got %s want nullable", asJSON(theme))
+	}
+}
@@ -0,0 +1,216 @@
+package restfulspec
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// goPackageName returns the name of the package that declares the named type.
+func goPackageName(st reflect.Type) string {
+	// String() is qualified by the package name, e.g. restfulspec.Sample
+	return strings.SplitN(st.String(), ".", 2)[0]
+}
+
+func setGoTypeExtension(b definitionBuilder, sm *spec.Schema, st reflect.Type) {
+	if !b.Config.EmitGoTypeExtension || st.Name() == "" || st.PkgPath() == "" {
+		return
+	}
+	initPropExtensions(&sm.Extensions)
+	sm.Extensions["x-go-type"] = map[string]interface{}{
+		"import": map[string]interface{}{
+			"package": st.PkgPath(),
+			"alias":   goPackageName(st),
+		},
+		"type": st.Name(),
+	}
+}
+
+func setGoTypeNameExtensions(b definitionBuilder, sm *spec.Schema, st reflect.Type) {
+	if !b.Config.EmitGoTypeNameExtensions || st.Name() == "" || st.PkgPath() == "" {
+		return
+	}
+	initPropExtensions(&sm.Extensions)
+	sm.Extensions["x-go-package"] = st.PkgPath()
+	sm.Extensions["x-go-type-name"] = st.Name()
+}
+
+// addExtraProp sets a JSON Schema keyword that the spec package does not model.
+func addExtraProp(sm *spec.Schema, key string, value interface{}) {
+	if sm.ExtraProps == nil {
+		sm.ExtraProps = map[string]interface{}{}
+	}
+	sm.ExtraProps[key] = value
+}
+
+// setSchemaID sets the $id keyword of JSON Schema draft-06 and later.
+// The ID field of the spec package is serialized as the draft-04 id keyword instead.
+func setSchemaID(b definitionBuilder, sm *spec.Schema, st reflect.Type) {
+	if b.Config.SchemaIDFunc == nil {
+		return
+	}
+	if id := b.Config.SchemaIDFunc(st); id != "" {
+		addExtraProp(sm, "$id", id)
+	}
+}
+
+func setConditionalSchema(b definitionBuilder, sm *spec.Schema, st reflect.Type) {
+	if b.Config.ConditionalSchemaFunc == nil {
+		return
+	}
+	cond := b.Config.ConditionalSchemaFunc(st)
+	if cond == nil || cond.If == nil {
+		return
+	}
+	addExtraProp(sm, "if", cond.If)
+	if cond.Then != nil {
+		addExtraProp(sm, "then", cond.Then)
+	}
+	if cond.Else != nil {
+		addExtraProp(sm, "else", cond.Else)
+	}
+}
+
+func setSchemaVersion(b definitionBuilder, sm *spec.Schema) {
+	if b.Config.EmitSchemaVersion != "" && b.Config.SchemaVersionPlacement == SchemaVersionOnDefinitions {
+		sm.Schema = spec.SchemaURL(b.Config.EmitSchemaVersion)
+	}
+}
+
+func setDefinitionMetadata(b definitionBuilder, sm *spec.Schema, st reflect.Type) {
+	setGoTypeExtension(b, sm, st)
+	setGoTypeNameExtensions(b, sm, st)
+	setSchemaID(b, sm, st)
+	setSchemaVersion(b, sm)
+	setGoDocDescription(b, sm, st)
+	setSchemaMetadata(sm, st)
+	setXMLName(sm, st)
+	setDependentRequired(b, sm, st)
+}
+
+// setDependentRequired sets the dependentRequired keyword of JSON Schema draft 2019-09 of the definition of a struct
+// from the dependentRequired tags of its fields, typically of a blank field:
+//
+//	_ struct{} `dependentRequired:"creditCard:billingAddress,cardHolder|iban:bic"`
+//
+// Each pair, separated by |, names the property whose presence requires the properties after the colon.
+// The keyword is set only if Config.JSONSchemaDraft is 2019-09 or later. The spec package does not model it.
+func setDependentRequired(b definitionBuilder, sm *spec.Schema, st reflect.Type) {
+	if st.Kind() != reflect.Struct || !jsonSchemaDraftAtLeast(b.Config, JSONSchemaDraft201909) {
+		return
+	}
+	dependencies := map[string][]string{}
+	for i := 0; i < st.NumField(); i++ {
+		tag := st.Field(i).Tag.Get("dependentRequired")
+		if tag == "" {
+			continue
+		}
+		for _, pair := range strings.Split(tag, "|") {
+			parts := strings.SplitN(pair, ":", 2)
+			trigger := strings.TrimSpace(parts[0])
+			if len(parts) != 2 || trigger == "" {
+				warn(b.Config, "dependentRequired pair is not trigger:dependent,...", "type", st.String(), "pair", pair)
+				continue
+			}
+			for _, each := range strings.Split(parts[1], ",") {
+				if each = strings.TrimSpace(each); each != "" && !containsString(dependencies[trigger], each) {
+					dependencies[trigger] = append(dependencies[trigger], each)
+				}
+			}
+		}
+	}
+	if len(dependencies) == 0 {
+		return
+	}
+	for trigger, dependents := range dependencies {
+		for _, each := range append([]string{trigger}, dependents...) {
+			if _, ok := sm.Properties[each]; !ok {
+				warn(b.Config, "dependentRequired names an unknown property", "type", st.String(), "property", each)
+			}
+		}
+	}
+	addExtraProp(sm, "dependentRequired", dependencies)
+}
+
+// sortProperties orders the properties of the definition of a struct, once collected, according to Config.PropertySort.
+func sortProperties(b definitionBuilder, sm *spec.Schema, st reflect.Type) {
+	switch b.Config.PropertySort {
+	case SortAlpha:
+		for name, prop := range sm.Properties {
+			delete(prop.Extensions, "x-order")
+			sm.Properties[name] = prop
+		}
+	case SortByTag:
+		setPropertyOrder(b, sm, st)
+	}
+}
+
+// setPropertyOrder sets the x-order extension of the properties of the fields of the struct with a x-order tag.
+func setPropertyOrder(b definitionBuilder, sm *spec.Schema, st reflect.Type) {
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if isEmbeddedStruct(field) && !hasNamedJSONTag(field) {
+			// its properties are part of the definition
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			setPropertyOrder(b, sm, embedded)
+			continue
+		}
+		tag := field.Tag.Get("x-order")
+		prop, ok := sm.Properties[b.jsonNameOfField(field)]
+		if tag == "" || !ok {
+			continue
+		}
+		order, err := strconv.Atoi(tag)
+		if err != nil {
+			warn(b.Config, "x-order tag is not an integer, property is not ordered", "field", field.Name, "tag", tag)
+			continue
+		}
+		// a float64 like decoded JSON, the spec package reads no other number
+		prop.AddExtension("x-order", float64(order))
+		sm.Properties[b.jsonNameOfField(field)] = prop
+	}
+}
+
+// setXMLName sets the XML name of the definition of a struct from the xml tag of its XMLName field.
+func setXMLName(sm *spec.Schema, st reflect.Type) {
+	if st.Kind() != reflect.Struct {
+		return
+	}
+	field, ok := st.FieldByName("XMLName")
+	if !ok || field.Type != reflect.TypeOf(xml.Name{}) {
+		return
+	}
+	name := strings.Split(field.Tag.Get("xml"), ",")[0]
+	if name == "" {
+		return
+	}
+	sm.XML = xmlObject(sm.XML)
+	sm.XML.Namespace, sm.XML.Name = xmlNamespaceAndName(name)
+}
+
+// setSchemaMetadata adds the SchemaMetadata of the type, or a pointer to it, to the extensions of the definition.
+func setSchemaMetadata(sm *spec.Schema, st reflect.Type) {
+	provider, ok := reflect.New(st).Elem().Interface().(SchemaMetadataProvider)
+	if !ok {
+		if provider, ok = reflect.New(st).Interface().(SchemaMetadataProvider); !ok {
+			return
+		}
+	}
+	for key, value := range provider.SchemaMetadata() {
+		setExtension(&sm.VendorExtensible, key, value)
+	}
+}
+
+// setGoDocDescription sets the description of the definition, if it has none, to that of the GoDocProvider.
+func setGoDocDescription(b definitionBuilder, sm *spec.Schema, st reflect.Type) {
+	if b.Config.GoDocProvider == nil || sm.Description != "" {
+		return
+	}
+	sm.Description = b.Config.GoDocProvider(st)
+}
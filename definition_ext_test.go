@@ -0,0 +1,241 @@
+package restfulspec
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/spec"
+)
+
+type Dictionary map[string]Item
+
+type WithAnonymous struct {
+	Anonymous struct{ Name string }
+}
+
+func TestGoTypeExtension(t *testing.T) {
+	cfg := Config{EmitGoTypeExtension: true}
+	d := definitionsFromStructWithConfig(Sample{}, cfg)
+
+	goType, ok := d["restfulspec.Sample"].Extensions["x-go-type"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("missing x-go-type extension")
+	}
+	if got, want := goType["type"], "Sample"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	imp := goType["import"].(map[string]interface{})
+	if got, want := imp["package"], "github.com/emicklei/go-restful-openapi/v2"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := imp["alias"], "restfulspec"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	d = definitionsFromStructWithConfig(Dictionary{}, cfg)
+	if _, ok := d["restfulspec.Dictionary"].Extensions["x-go-type"]; !ok {
+		t.Errorf("missing x-go-type extension on map definition")
+	}
+
+	d = definitionsFromStructWithConfig(WithAnonymous{}, cfg)
+	for name, each := range d {
+		if _, ok := each.Extensions["x-go-type"]; ok != (name == "restfulspec.WithAnonymous") {
+			t.Errorf("%s: only named types must have a x-go-type extension", name)
+		}
+	}
+
+	d = definitionsFromStruct(Sample{})
+	if _, ok := d["restfulspec.Sample"].Extensions["x-go-type"]; ok {
+		t.Errorf("x-go-type extension must not be emitted by default")
+	}
+}
+
+func TestGoTypeNameExtensions(t *testing.T) {
+	d := definitionsFromStructWithConfig(Sample{}, Config{EmitGoTypeNameExtensions: true})
+
+	for _, name := range []string{"restfulspec.Sample", "restfulspec.Item"} {
+		ext := d[name].Extensions
+		if got, want := ext["x-go-package"], "github.com/emicklei/go-restful-openapi/v2"; got != want {
+			t.Errorf("%s: got %v want %v", name, got, want)
+		}
+		if _, ok := ext["x-go-type"]; ok {
+			t.Errorf("%s: unexpected x-go-type extension", name)
+		}
+	}
+	if got, want := d["restfulspec.Item"].Extensions["x-go-type-name"], "Item"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestSchemaIDFunc(t *testing.T) {
+	cfg := Config{SchemaIDFunc: func(t reflect.Type) string {
+		if t.Name() == "Item" {
+			return ""
+		}
+		return "https://example.com/schemas/" + t.Name()
+	}}
+	d := definitionsFromStructWithConfig(Sample{}, cfg)
+
+	data, err := json.Marshal(d["restfulspec.Sample"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"$id":"https://example.com/schemas/Sample"`) {
+		t.Errorf("missing $id in %s", data)
+	}
+	if _, ok := d["restfulspec.Item"].ExtraProps["$id"]; ok {
+		t.Errorf("unexpected $id for empty result")
+	}
+	if _, ok := definitionsFromStruct(Sample{})["restfulspec.Sample"].ExtraProps["$id"]; ok {
+		t.Errorf("unexpected $id without SchemaIDFunc")
+	}
+}
+
+type Shipment struct {
+	Method  string `json:"method" enum:"pickup|delivery"`
+	Address string `json:"address,omitempty"`
+}
+
+func TestConditionalSchemaFunc(t *testing.T) {
+	cfg := Config{ConditionalSchemaFunc: func(t reflect.Type) *ConditionalSchema {
+		if t != reflect.TypeOf(Shipment{}) {
+			return nil
+		}
+		return &ConditionalSchema{
+			If: new(spec.Schema).SetProperty("method", spec.Schema{
+				SchemaProps: spec.SchemaProps{Enum: []interface{}{"delivery"}},
+			}),
+			Then: &spec.Schema{SchemaProps: spec.SchemaProps{Required: []string{"address"}}},
+		}
+	}}
+	d := definitionsFromStructWithConfig(Shipment{}, cfg)
+
+	data, err := json.Marshal(d["restfulspec.Shipment"].ExtraProps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"if":{"properties":{"method":{"enum":["delivery"]}}},"then":{"required":["address"]}}`
+	if string(data) != expected {
+		t.Errorf("got %s want %s", data, expected)
+	}
+}
+
+type Reservation struct {
+	From time.Time
+	To   time.Time
+}
+
+func (Reservation) SchemaMetadata() map[string]interface{} {
+	return map[string]interface{}{"x-validation": "From before To"}
+}
+
+type Seat struct {
+	Row int
+}
+
+func (*Seat) SchemaMetadata() map[string]interface{} {
+	return map[string]interface{}{"x-validation": map[string]interface{}{"Row": "positive"}, "x-minRow": 1}
+}
+
+func TestSchemaMetadataProvider(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Reservation{})
+	db.addModelFrom(Seat{})
+	if got, want := db.Definitions["restfulspec.Reservation"].Extensions["x-validation"], "From before To"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	seat := db.Definitions["restfulspec.Seat"]
+	if got, want := seat.Extensions["x-minRow"], 1; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if _, ok := seat.Extensions["x-validation"].(map[string]interface{}); !ok {
+		t.Errorf("got %v", asJSON(seat))
+	}
+}
+
+type Sender struct {
+	Street string `json:"street" x-order:"2"`
+}
+
+type Letter struct {
+	Sender
+	Zip     string `json:"zip" x-order:"1"`
+	Body    string `json:"body"`
+	Subject string `json:"subject" x-order:"0"`
+	Address string `json:"address"`
+}
+
+func TestPropertySort(t *testing.T) {
+	order := func(mode PropertySortMode) string {
+		db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{PropertySort: mode}}
+		db.addModelFrom(Letter{})
+		data, _ := json.Marshal(db.Definitions["restfulspec.Letter"].Properties)
+		return string(data)
+	}
+	want := []string{"address", "body", "street", "subject", "zip"}
+	for _, mode := range []PropertySortMode{SortNone, SortAlpha} {
+		if got := order(mode); !inOrder(got, want) {
+			t.Errorf("mode %d: got %s want order %v", mode, got, want)
+		}
+	}
+	want = []string{"subject", "zip", "street", "address", "body"}
+	if got := order(SortByTag); !inOrder(got, want) {
+		t.Errorf("got %s want order %v", got, want)
+	}
+	if err := validateConfig(Config{PropertySort: SortByTag + 1}); err == nil {
+		t.Error("expected error for unknown property sort")
+	}
+}
+
+// inOrder returns true if the quoted names occur in the JSON in the order given.
+func inOrder(data string, names []string) bool {
+	at := -1
+	for _, each := range names {
+		i := strings.Index(data, `"`+each+`"`)
+		if i < at {
+			return false
+		}
+		at = i
+	}
+	return true
+}
+
+type Checkout struct {
+	_              struct{} `dependentRequired:"creditCard:billingAddress, cardHolder|iban:bic|iban:bic,country|broken"`
+	CreditCard     string   `json:"creditCard,omitempty"`
+	BillingAddress string   `json:"billingAddress,omitempty"`
+	CardHolder     string   `json:"cardHolder,omitempty"`
+	IBAN           string   `json:"iban,omitempty"`
+	BIC            string   `json:"bic,omitempty"`
+}
+
+func TestDependentRequired(t *testing.T) {
+	logger := new(recordingWarnLogger)
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{Logger: logger}}
+	db.addModelFrom(Checkout{})
+	if extra := db.Definitions["restfulspec.Checkout"].ExtraProps; extra != nil || len(logger.warnings) != 0 {
+		t.Errorf("got %v and warnings %v want none before draft 2019-09", extra, logger.warnings)
+	}
+
+	db = definitionBuilder{Definitions: spec.Definitions{}, Config: Config{Logger: logger, JSONSchemaDraft: JSONSchemaDraft201909}}
+	db.addModelFrom(Checkout{})
+	checkout := db.Definitions["restfulspec.Checkout"]
+	if _, ok := checkout.Properties["_"]; ok {
+		t.Errorf("unexpected property _ in %v", asJSON(checkout))
+	}
+	data, _ := json.Marshal(checkout.ExtraProps["dependentRequired"])
+	if got, want := string(data), `{"creditCard":["billingAddress","cardHolder"],"iban":["bic","country"]}`; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := len(logger.warnings), 2; got != want {
+		t.Errorf("got %v want %v: %v", got, want, logger.warnings)
+	}
+
+	db.addModelFrom(Seat{})
+	if extra := db.Definitions["restfulspec.Seat"].ExtraProps; extra != nil {
+		t.Errorf("got %v want none", extra)
+	}
+}
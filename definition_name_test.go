@@ -1,7 +1,11 @@
 package restfulspec
 
 import (
+	"sort"
+	"strings"
 	"testing"
+
+	"github.com/go-openapi/spec"
 )
 
 func TestGoLowerCamelCasedNameHandler_DefaultDefinitionName(t *testing.T) {
@@ -92,3 +96,32 @@ func TestLowerSnakeCasedNameHandler_DefaultDefinitionName(t *testing.T) {
 		}
 	}
 }
+
+type normalizedNames struct {
+	UserName  string
+	HomePage  string `json:"home_page"`
+	Secret    string `json:"-"`
+	LastLogin string `json:"lastLogin,omitempty"`
+}
+
+func TestPropertyNameNormalizer(t *testing.T) {
+	kebab := func(name string) string {
+		return strings.ReplaceAll(LowerSnakeCasedNameHandler(name), "_", "-")
+	}
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{PropertyNameNormalizer: kebab}}
+	db.addModelFrom(normalizedNames{})
+	schema := db.Definitions["restfulspec.normalizedNames"]
+
+	for _, name := range []string{"user-name", "home-page", "last-login"} {
+		if _, ok := schema.Properties[name]; !ok {
+			t.Errorf("missing property %s in %v", name, schema.Properties)
+		}
+	}
+	if got, want := len(schema.Properties), 3; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	sort.Strings(schema.Required)
+	if got, want := strings.Join(schema.Required, ","), "home-page,user-name"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+package restfulspec
+
+import (
+	"mime/multipart"
+	"os"
+	"reflect"
+
+	"github.com/go-openapi/spec"
+)
+
+// FormDataSchema is a marker interface for the sample of a Route's Reads.
+// If the sample implements it then each of its fields is documented as a separate formData parameter
+// instead of the sample being documented as a body parameter.
+type FormDataSchema interface {
+	FormDataSchema()
+}
+
+var (
+	formDataSchemaType = reflect.TypeOf((*FormDataSchema)(nil)).Elem()
+	fileHeaderType     = reflect.TypeOf(multipart.FileHeader{})
+	osFileType         = reflect.TypeOf(os.File{})
+)
+
+const (
+	mimeFormURLEncoded    = "application/x-www-form-urlencoded"
+	mimeMultipartFormData = "multipart/form-data"
+)
+
+// isFormDataSample returns true if the sample, or a pointer to it, implements FormDataSchema.
+func isFormDataSample(sample interface{}) bool {
+	if sample == nil {
+		return false
+	}
+	st := reflect.TypeOf(sample)
+	return st.Implements(formDataSchemaType) || reflect.PtrTo(st).Implements(formDataSchemaType)
+}
+
+// buildFormDataParameters returns a formData parameter for each field of the struct type.
+func buildFormDataParameters(st reflect.Type, cfg Config) (params []spec.Parameter) {
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		return nil
+	}
+	b := definitionBuilder{Definitions: spec.Definitions{}, Config: cfg}
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if field.PkgPath != "" {
+			// not exported
+			continue
+		}
+		if field.Anonymous && !hasNamedJSONTag(field) {
+			// fields of embedded structs are fields of the form
+			params = append(params, buildFormDataParameters(field.Type, cfg)...)
+			continue
+		}
+		if isFileType(field.Type) {
+			if jsonName := b.jsonNameOfField(field); jsonName != "" {
+				p := *spec.FileParam(jsonName)
+				p.Description = field.Tag.Get("description")
+				p.Required = b.isPropertyRequired(field)
+				params = append(params, p)
+			}
+			continue
+		}
+		jsonName, _, prop := b.buildProperty(field, &spec.Schema{}, keyFrom(st, cfg))
+		if jsonName == "" {
+			continue
+		}
+		params = append(params, buildFormDataParameter(jsonName, prop, b.isPropertyRequired(field)))
+	}
+	return params
+}
+
+// isFileType returns true for the types of uploaded files, multipart.FileHeader and os.File, or pointers to them.
+func isFileType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t == fileHeaderType || t == osFileType
+}
+
+// hasFileParameter returns true if one of the parameters is a file.
+func hasFileParameter(params []spec.Parameter) bool {
+	for _, each := range params {
+		if each.Type == "file" {
+			return true
+		}
+	}
+	return false
+}
+
+// buildFormDataParameter converts the property schema of a field into the simple schema of a formData parameter.
+// Form values cannot be objects, so fields that are not primitives or arrays of primitives are documented as strings.
+func buildFormDataParameter(name string, prop spec.Schema, required bool) spec.Parameter {
+	p := *spec.FormDataParam(name)
+	p.Description = prop.Description
+	p.Required = required
+	p.Type = formDataType(prop)
+	if p.Type == arrayType {
+		p.Items = spec.NewItems()
+		if p.Items.Type = formDataType(*prop.Items.Schema); p.Items.Type == arrayType {
+			p.Items.Type = "string"
+		}
+		p.Items.Format = prop.Items.Schema.Format
+		p.Items.Enum = prop.Items.Schema.Enum
+		p.CollectionFormat = "multi"
+		return p
+	}
+	p.Format = prop.Format
+	p.Enum = prop.Enum
+	p.Default = prop.Default
+	p.Minimum = prop.Minimum
+	p.Maximum = prop.Maximum
+	p.Pattern = prop.Pattern
+	return p
+}
+
+func formDataType(prop spec.Schema) string {
+	if len(prop.Type) == 0 || prop.Ref.String() != "" {
+		return "string"
+	}
+	switch t := prop.Type[0]; t {
+	case "string", "integer", "number", "boolean":
+		return t
+	case arrayType:
+		if prop.Items != nil && prop.Items.Schema != nil {
+			return arrayType
+		}
+	}
+	return "string"
+}
+
+// formDataConsumes returns the consumes of an operation with the formData parameters.
+// Files can only be uploaded as multipart/form-data, other forms can also be url-encoded.
+func formDataConsumes(consumes []string, params []spec.Parameter) []string {
+	if hasFileParameter(params) {
+		if containsString(consumes, mimeMultipartFormData) {
+			return consumes
+		}
+		return []string{mimeMultipartFormData}
+	}
+	if containsString(consumes, mimeFormURLEncoded) || containsString(consumes, mimeMultipartFormData) {
+		return consumes
+	}
+	return []string{mimeFormURLEncoded}
+}
@@ -0,0 +1,122 @@
+package restfulspec
+
+import (
+	"mime/multipart"
+	"net/http"
+	"os"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+)
+
+type Credentials struct {
+	Remember bool `json:"remember" optional:"true"`
+}
+
+type LoginForm struct {
+	Credentials
+	User     string   `json:"user" description:"name of the user"`
+	Password string   `json:"password"`
+	Scopes   []string `json:"scopes" optional:"true"`
+	Attempts int      `json:"attempts" minimum:"1" optional:"true"`
+	Role     string   `json:"role" enum:"admin|user" default:"user" optional:"true"`
+	Profile  *Item    `json:"profile" optional:"true"`
+	Ignored  string   `json:"-"`
+	internal string
+}
+
+func (LoginForm) FormDataSchema() {}
+
+func TestFormDataSchema(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/login")
+	ws.Consumes(restful.MIME_JSON)
+	ws.Route(ws.POST("").To(dummy).Reads(LoginForm{}))
+
+	p := buildPaths(ws, Config{})
+	t.Log(asJSON(p))
+
+	op := p.Paths["/tests/login"].Post
+	if got, want := op.Consumes, []string{mimeFormURLEncoded}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("got %v want %v", got, want)
+	}
+	params := map[string]int{}
+	for i, each := range op.Parameters {
+		if each.In != "formData" {
+			t.Errorf("%s: got in %v", each.Name, each.In)
+		}
+		params[each.Name] = i
+	}
+	if got, want := len(op.Parameters), 7; got != want {
+		t.Fatalf("got %d parameters want %d", got, want)
+	}
+	user := op.Parameters[params["user"]]
+	if user.Type != "string" || !user.Required || user.Description != "name of the user" {
+		t.Errorf("got user %#v", user)
+	}
+	if remember := op.Parameters[params["remember"]]; remember.Type != "boolean" || remember.Required {
+		t.Errorf("got remember %#v", remember)
+	}
+	scopes := op.Parameters[params["scopes"]]
+	if scopes.Type != "array" || scopes.Items.Type != "string" || scopes.CollectionFormat != "multi" {
+		t.Errorf("got scopes %#v", scopes)
+	}
+	if attempts := op.Parameters[params["attempts"]]; attempts.Type != "integer" || *attempts.Minimum != 1 {
+		t.Errorf("got attempts %#v", attempts)
+	}
+	if role := op.Parameters[params["role"]]; len(role.Enum) != 2 || role.Default != "user" {
+		t.Errorf("got role %#v", role)
+	}
+	if profile := op.Parameters[params["profile"]]; profile.Type != "string" {
+		t.Errorf("got profile %#v", profile)
+	}
+
+	if _, ok := buildDefinitions(ws, Config{})["restfulspec.LoginForm"]; ok {
+		t.Errorf("unexpected definition of form")
+	}
+}
+
+type UploadForm struct {
+	Title      string                `json:"title"`
+	Attachment *multipart.FileHeader `json:"attachment" description:"the file to upload"`
+	Thumbnail  *os.File              `json:"thumbnail" optional:"true"`
+}
+
+func (UploadForm) FormDataSchema() {}
+
+func upload(req *restful.Request, resp *restful.Response) {
+	file, header, err := req.Request.FormFile("attachment")
+	if err != nil {
+		resp.WriteError(http.StatusBadRequest, err)
+		return
+	}
+	defer file.Close()
+	resp.WriteEntity(map[string]interface{}{"title": req.Request.FormValue("title"), "size": header.Size})
+}
+
+func TestFormDataFileParameters(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/tests/uploads")
+	ws.Route(ws.POST("").To(upload).Consumes(mimeFormURLEncoded).Reads(UploadForm{}))
+
+	p := buildPaths(ws, Config{})
+	t.Log(asJSON(p))
+
+	op := p.Paths["/tests/uploads"].Post
+	if got := op.Consumes; len(got) != 1 || got[0] != mimeMultipartFormData {
+		t.Errorf("got consumes %v", got)
+	}
+	if got, want := len(op.Parameters), 3; got != want {
+		t.Fatalf("got %d parameters want %d", got, want)
+	}
+	attachment := op.Parameters[1]
+	if attachment.Name != "attachment" || attachment.Type != "file" || attachment.In != "formData" || !attachment.Required {
+		t.Errorf("got attachment %#v", attachment)
+	}
+	if got, want := attachment.Description, "the file to upload"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if thumbnail := op.Parameters[2]; thumbnail.Type != "file" || thumbnail.Required {
+		t.Errorf("got thumbnail %#v", thumbnail)
+	}
+}
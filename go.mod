@@ -2,9 +2,24 @@ module github.com/emicklei/go-restful-openapi/v2
 
 require (
 	github.com/emicklei/go-restful/v3 v3.7.3
+	github.com/fsnotify/fsnotify v1.5.4
 	github.com/go-openapi/spec v0.20.9
+	github.com/go-openapi/swag v0.19.15
 	github.com/gogo/protobuf v1.3.2
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/go-openapi/jsonpointer v0.19.5 // indirect
+	github.com/go-openapi/jsonreference v0.20.0 // indirect
+	github.com/josharian/intern v1.0.0 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
+	github.com/mailru/easyjson v0.7.6 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
+	golang.org/x/sys v0.0.0-20220412211240-33da011f77ad // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
 
-go 1.13
+go 1.21
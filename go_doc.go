@@ -0,0 +1,142 @@
+package restfulspec
+
+import (
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// goDocPackages caches the documentation of the packages read by GoDocDescription, by import path.
+var goDocPackages = struct {
+	sync.Mutex
+	docs map[string]*doc.Package
+}{docs: map[string]*doc.Package{}}
+
+// GoDocDescription returns the doc comment of the declaration of a named type, read from the source of
+// its package. It can be used as the GoDocProvider of the Config and returns an empty string if the
+// source cannot be found, e.g. for a program built elsewhere.
+func GoDocDescription(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Name() == "" || t.PkgPath() == "" {
+		return ""
+	}
+	pkg := goDocPackage(t.PkgPath())
+	if pkg == nil {
+		return ""
+	}
+	for _, each := range pkg.Types {
+		if each.Name == t.Name() {
+			return strings.TrimSpace(each.Doc)
+		}
+	}
+	return ""
+}
+
+// GoDocFieldDescriptions returns a function, for the FieldDescriptionFunc of the Config, that returns the comment
+// of a struct field declared in the source of one of the packages, either above it or at the end of its line.
+// A field does not tell its struct, so it is found by its name and tag; if fields of several structs
+// have those and different comments, it returns an empty string.
+func GoDocFieldDescriptions(importPaths ...string) func(field reflect.StructField) string {
+	comments := map[string]string{}
+	ambiguous := map[string]bool{}
+	for _, importPath := range importPaths {
+		pkg := goDocPackage(importPath)
+		if pkg == nil {
+			continue
+		}
+		for _, typ := range pkg.Types {
+			for _, each := range typ.Decl.Specs {
+				ts, ok := each.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					comment := fieldComment(field)
+					for _, name := range field.Names {
+						key := name.Name + "\x00" + fieldTag(field)
+						if existing, ok := comments[key]; ok && existing != comment {
+							ambiguous[key] = true
+						}
+						comments[key] = comment
+					}
+				}
+			}
+		}
+	}
+	return func(field reflect.StructField) string {
+		key := field.Name + "\x00" + string(field.Tag)
+		if ambiguous[key] {
+			return ""
+		}
+		return comments[key]
+	}
+}
+
+// fieldComment returns the doc comment of the field or else its line comment.
+func fieldComment(field *ast.Field) string {
+	if field.Doc != nil {
+		return strings.TrimSpace(field.Doc.Text())
+	}
+	if field.Comment != nil {
+		return strings.TrimSpace(field.Comment.Text())
+	}
+	return ""
+}
+
+// fieldTag returns the tag of the field as reflect.StructField has it.
+func fieldTag(field *ast.Field) string {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return tag
+}
+
+// goDocPackage returns the documentation of the package, nil if its source cannot be read.
+func goDocPackage(importPath string) *doc.Package {
+	goDocPackages.Lock()
+	defer goDocPackages.Unlock()
+	if pkg, ok := goDocPackages.docs[importPath]; ok {
+		return pkg
+	}
+	pkg := readGoDocPackage(importPath)
+	goDocPackages.docs[importPath] = pkg
+	return pkg
+}
+
+func readGoDocPackage(importPath string) *doc.Package {
+	bp, err := build.Import(importPath, ".", 0)
+	if err != nil {
+		return nil
+	}
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(bp.GoFiles))
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(bp.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil
+		}
+		files = append(files, f)
+	}
+	pkg, err := doc.NewFromFiles(fset, files, importPath, doc.AllDecls)
+	if err != nil {
+		return nil
+	}
+	return pkg
+}
@@ -0,0 +1,85 @@
+package restfulspec
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/spec"
+)
+
+func TestGoDocDescription(t *testing.T) {
+	if got, want := GoDocDescription(reflect.TypeOf(TagGroup{})), "TagGroup is an element of the x-tagGroups extension of the Swagger object."; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+	if got := GoDocDescription(reflect.TypeOf(time.Duration(0))); got == "" {
+		t.Error("expected description of time.Duration")
+	}
+	if got := GoDocDescription(reflect.TypeOf(struct{ A int }{})); got != "" {
+		t.Errorf("got %q want empty", got)
+	}
+}
+
+// Recipe is documented by its SwaggerDoc.
+type Recipe struct {
+	Name string
+}
+
+func (Recipe) SwaggerDoc() map[string]string {
+	return map[string]string{"": "a recipe"}
+}
+
+func TestGoDocProvider(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{GoDocProvider: GoDocDescription}}
+	db.addModelFrom(TagGroup{})
+	db.addModelFrom(Recipe{})
+	if got, want := db.Definitions["restfulspec.TagGroup"].Description, "TagGroup is an element of the x-tagGroups extension of the Swagger object."; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+	if got, want := db.Definitions["restfulspec.Recipe"].Description, "a recipe"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestGoDocFieldDescriptions(t *testing.T) {
+	describe := GoDocFieldDescriptions("github.com/emicklei/go-restful-openapi/v2")
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{FieldDescriptionFunc: describe}}
+	db.addModelFrom(LogoInfo{})
+	props := db.Definitions["restfulspec.LogoInfo"].Properties
+	if got, want := props["url"].Description, "URL of the logo image"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+	if got, want := props["altText"].Description, "AltText of the logo image"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+	field, _ := reflect.TypeOf(Recipe{}).FieldByName("Name")
+	if got := describe(field); got != "" {
+		t.Errorf("got %q want empty", got)
+	}
+}
+
+type Ingredient struct {
+	Name string `description:"name of the ingredient"`
+	Unit string
+}
+
+func TestFieldDescriptionFunc(t *testing.T) {
+	var called []string
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{FieldDescriptionFunc: func(field reflect.StructField) string {
+		called = append(called, field.Name)
+		return "the " + field.Name
+	}}}
+	db.addModelFrom(Ingredient{})
+	props := db.Definitions["restfulspec.Ingredient"].Properties
+	if got, want := props["Name"].Description, "name of the ingredient"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+	if got, want := props["Unit"].Description, "the Unit"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+	for _, each := range called {
+		if each == "Name" {
+			t.Error("should not be called for a field with a description tag")
+		}
+	}
+}
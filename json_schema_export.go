@@ -0,0 +1,214 @@
+package restfulspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// JSONSchemaExport builds the definitions of the types, given as sample values or as reflect.Type values,
+// and returns the JSON Schema document of each type by its definition name.
+// Each document is self-contained: the definitions it refers to, directly or indirectly,
+// are included in its definitions keyword so that its #/definitions/ references resolve,
+// and the references of a recursive type to itself are # references to the document.
+// For JSON Schema 2019-09 and later, see Config.JSONSchemaDraft and OpenAPIVersion31, they are included
+// in its $defs keyword instead and referenced as #/$defs/.
+func JSONSchemaExport(types []interface{}, config Config) (map[string][]byte, error) {
+	definitions := spec.Definitions{}
+	builder := definitionBuilder{Definitions: definitions, Config: config}
+	names := make([]string, 0, len(types))
+	for i, each := range types {
+		st, ok := each.(reflect.Type)
+		if !ok {
+			st = reflect.TypeOf(each)
+		}
+		if st == nil {
+			return nil, fmt.Errorf("type at index %d is nil", i)
+		}
+		for st.Kind() == reflect.Ptr || builder.isSliceOrArrayType(st.Kind()) {
+			st = st.Elem()
+		}
+		builder.addModel(st, "")
+		name := keyFrom(st, config)
+		if _, ok := definitions[name]; !ok {
+			return nil, fmt.Errorf("type %s has no definition", st)
+		}
+		names = append(names, name)
+	}
+	dropUnknownTypeNames(config, definitions)
+	files := make(map[string][]byte, len(names))
+	for _, name := range names {
+		root := definitions[name]
+		root.Definitions = spec.Definitions{}
+		addReferencedDefinitions(&root, definitions, root.Definitions)
+		delete(root.Definitions, name)
+		if len(root.Definitions) == 0 {
+			root.Definitions = nil
+		}
+		data, err := json.MarshalIndent(root, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal schema of %s: %v", name, err)
+		}
+		if data, err = selfContainedDocument(data, name, jsonSchemaDraftAtLeast(config, JSONSchemaDraft201909)); err != nil {
+			return nil, fmt.Errorf("rewrite references of %s: %v", name, err)
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// addReferencedDefinitions adds the definitions that the schema refers to, directly or indirectly, to the collected ones.
+func addReferencedDefinitions(s *spec.Schema, definitions, collected spec.Definitions) {
+	walkSchemaRefs(s, func(ref string) {
+		name := strings.TrimPrefix(ref, definitionRoot)
+		if _, ok := collected[name]; ok || name == ref {
+			return
+		}
+		def, ok := definitions[name]
+		if !ok {
+			return
+		}
+		collected[name] = def
+		addReferencedDefinitions(&def, definitions, collected)
+	})
+}
+
+// selfContainedDocument returns the JSON Schema document of the named definition with its #/definitions/ references
+// to the definition itself, in all of its subschemas, rewritten to #. If defs is true, its definitions keyword is
+// renamed to $defs and its other #/definitions/ references are rewritten to #/$defs/.
+func selfContainedDocument(data []byte, name string, defs bool) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	refRoot := definitionRoot
+	if defs {
+		refRoot = "#/$defs/"
+		if each, ok := doc["definitions"]; ok {
+			delete(doc, "definitions")
+			doc["$defs"] = each
+		}
+	}
+	rewriteDefinitionRefs(doc, name, refRoot)
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func rewriteDefinitionRefs(value interface{}, root, refRoot string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, definitionRoot) {
+			if name := strings.TrimPrefix(ref, definitionRoot); name == root {
+				v["$ref"] = "#"
+			} else {
+				v["$ref"] = refRoot + name
+			}
+		}
+		for _, each := range v {
+			rewriteDefinitionRefs(each, root, refRoot)
+		}
+	case []interface{}:
+		for _, each := range v {
+			rewriteDefinitionRefs(each, root, refRoot)
+		}
+	}
+}
+
+// walkSchemas calls the function with the schema and each of its subschemas, which it can modify.
+func walkSchemas(s *spec.Schema, fn func(s *spec.Schema)) {
+	if s == nil {
+		return
+	}
+	fn(s)
+	for name, each := range s.Properties {
+		walkSchemas(&each, fn)
+		s.Properties[name] = each
+	}
+	for name, each := range s.PatternProperties {
+		walkSchemas(&each, fn)
+		s.PatternProperties[name] = each
+	}
+	if s.Items != nil {
+		walkSchemas(s.Items.Schema, fn)
+		for i := range s.Items.Schemas {
+			walkSchemas(&s.Items.Schemas[i], fn)
+		}
+	}
+	if s.AdditionalProperties != nil {
+		walkSchemas(s.AdditionalProperties.Schema, fn)
+	}
+	for _, list := range [][]spec.Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for i := range list {
+			walkSchemas(&list[i], fn)
+		}
+	}
+	walkSchemas(s.Not, fn)
+	for _, key := range subschemaKeywords {
+		if sub := extraPropSchema(s, key); sub != nil {
+			walkSchemas(sub, fn)
+			s.ExtraProps[key] = sub
+		}
+	}
+}
+
+// walkSchemaRefs calls the function with each reference in the schema and its subschemas.
+func walkSchemaRefs(s *spec.Schema, fn func(ref string)) {
+	if s == nil {
+		return
+	}
+	if ref := s.Ref.String(); ref != "" {
+		fn(ref)
+	}
+	for _, each := range s.Properties {
+		walkSchemaRefs(&each, fn)
+	}
+	for _, each := range s.PatternProperties {
+		walkSchemaRefs(&each, fn)
+	}
+	if s.Items != nil {
+		walkSchemaRefs(s.Items.Schema, fn)
+		for i := range s.Items.Schemas {
+			walkSchemaRefs(&s.Items.Schemas[i], fn)
+		}
+	}
+	if s.AdditionalProperties != nil {
+		walkSchemaRefs(s.AdditionalProperties.Schema, fn)
+	}
+	for _, list := range [][]spec.Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for i := range list {
+			walkSchemaRefs(&list[i], fn)
+		}
+	}
+	walkSchemaRefs(s.Not, fn)
+	for _, key := range subschemaKeywords {
+		walkSchemaRefs(extraPropSchema(s, key), fn)
+	}
+}
+
+// subschemaKeywords are the keywords with a schema value that the spec package does not model
+// and that are kept in ExtraProps.
+var subschemaKeywords = []string{"contains", "propertyNames", "if", "then", "else"}
+
+// extraPropSchema returns the schema of the keyword kept in ExtraProps, or nil if there is none.
+// After a JSON round trip, e.g. by cloneSwagger, the value is a map instead of a schema.
+func extraPropSchema(s *spec.Schema, key string) *spec.Schema {
+	switch value := s.ExtraProps[key].(type) {
+	case *spec.Schema:
+		return value
+	case spec.Schema:
+		return &value
+	case map[string]interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil
+		}
+		var sub spec.Schema
+		if err := json.Unmarshal(data, &sub); err != nil {
+			return nil
+		}
+		return &sub
+	}
+	return nil
+}
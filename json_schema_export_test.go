@@ -0,0 +1,168 @@
+package restfulspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+type Folder struct {
+	Name    string   `json:"name"`
+	Folders []Folder `json:"folders"`
+	Owner   *Item    `json:"owner"`
+}
+
+func TestJSONSchemaExport(t *testing.T) {
+	files, err := JSONSchemaExport([]interface{}{&Folder{}, reflect.TypeOf(Item{})}, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("got %d files", len(files))
+	}
+	t.Log(string(files["restfulspec.Folder"]))
+
+	var folder spec.Schema
+	if err := json.Unmarshal(files["restfulspec.Folder"], &folder); err != nil {
+		t.Fatal(err)
+	}
+	// a recursive type refers to the document itself as its own definition is not included
+	if data := string(files["restfulspec.Folder"]); !strings.Contains(data, `"$ref": "#"`) ||
+		strings.Contains(data, "#/definitions/restfulspec.Folder") {
+		t.Errorf("got %s want $ref # for folders", data)
+	}
+	owner := folder.Properties["owner"].Ref
+	if got, want := owner.String(), "#/definitions/restfulspec.Item"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if _, ok := folder.Definitions["restfulspec.Item"]; !ok || len(folder.Definitions) != 1 {
+		t.Errorf("got definitions %v", folder.Definitions)
+	}
+
+	var item spec.Schema
+	if err := json.Unmarshal(files["restfulspec.Item"], &item); err != nil {
+		t.Fatal(err)
+	}
+	if item.Definitions != nil {
+		t.Errorf("got definitions %v", item.Definitions)
+	}
+
+	if _, err := JSONSchemaExport([]interface{}{nil}, Config{}); err == nil {
+		t.Errorf("expected error for nil type")
+	}
+	if _, err := JSONSchemaExport([]interface{}{func() {}}, Config{}); err == nil {
+		t.Errorf("expected error for func type")
+	}
+}
+
+func TestJSONSchemaExportDefs(t *testing.T) {
+	for _, config := range []Config{{OpenAPIVersion: OpenAPIVersion31}, {JSONSchemaDraft: JSONSchemaDraft202012}} {
+		files, err := JSONSchemaExport([]interface{}{Folder{}}, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc map[string]interface{}
+		if err := json.Unmarshal(files["restfulspec.Folder"], &doc); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := doc["definitions"]; ok {
+			t.Errorf("unexpected definitions in %s", files["restfulspec.Folder"])
+		}
+		defs, _ := doc["$defs"].(map[string]interface{})
+		if _, ok := defs["restfulspec.Item"]; !ok {
+			t.Errorf("missing restfulspec.Item in $defs of %s", files["restfulspec.Folder"])
+		}
+		// every reference must resolve within the document, as a 2020-12 validator requires
+		refs := 0
+		var check func(value interface{})
+		check = func(value interface{}) {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				if ref, ok := v["$ref"].(string); ok {
+					refs++
+					if _, ok := defs[strings.TrimPrefix(ref, "#/$defs/")]; ref != "#" && !ok {
+						t.Errorf("unresolved $ref %s", ref)
+					}
+				}
+				for _, each := range v {
+					check(each)
+				}
+			case []interface{}:
+				for _, each := range v {
+					check(each)
+				}
+			}
+		}
+		check(doc)
+		if got, want := refs, 2; got != want {
+			t.Errorf("got %v want %v refs in %s", got, want, files["restfulspec.Folder"])
+		}
+	}
+}
+
+// metaSchema2020 returns the compiler and the meta-schema of JSON Schema 2020-12, compiled from
+// the copy of https://json-schema.org/draft/2020-12/schema and its vocabularies in testdata/json-schema-2020-12,
+// so that nothing is loaded over the network.
+func metaSchema2020(t *testing.T) (*jsonschema.Compiler, *jsonschema.Schema) {
+	const root = "testdata/json-schema-2020-12"
+	c := jsonschema.NewCompiler()
+	c.Draft = jsonschema.Draft2020
+	c.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("unexpected load of %s", url)
+	}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel := strings.TrimSuffix(filepath.ToSlash(strings.TrimPrefix(path, root+string(filepath.Separator))), ".json")
+		return c.AddResource("https://json-schema.org/draft/2020-12/"+rel, bytes.NewReader(data))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, err := c.Compile("https://json-schema.org/draft/2020-12/schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, meta
+}
+
+func TestJSONSchemaExportIsValid2020(t *testing.T) {
+	c, meta := metaSchema2020(t)
+	files, err := JSONSchemaExport([]interface{}{Folder{}, Checkout{}, Tagged{}, Bundle{}},
+		Config{JSONSchemaDraft: JSONSchemaDraft202012, Logger: NopLogger{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		var doc interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		if err := meta.Validate(doc); err != nil {
+			t.Errorf("%s is not valid against the 2020-12 meta-schema: %#v\n%s", name, err, data)
+		}
+		// compiling resolves the references of the document
+		url := "https://example.com/" + name + ".json"
+		if err := c.AddResource(url, bytes.NewReader(data)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Compile(url); err != nil {
+			t.Errorf("%s does not compile: %v\n%s", name, err, data)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package restfulspec
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// Severity tells how serious a LintWarning is.
+type Severity int
+
+const (
+	// SeverityInfo is for documentation that could be better, such as a property without description.
+	SeverityInfo Severity = iota
+	// SeverityWarning is for schemas that are valid but probably not what was intended, such as an enum with one value.
+	SeverityWarning
+	// SeverityError is for schemas that are wrong, such as a format that does not belong to the type.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "info"
+}
+
+// LintWarning describes a problem in a Swagger document.
+type LintWarning struct {
+	Severity Severity
+	// Pointer is the JSON Pointer to the offending element, e.g. /definitions/User/properties/name
+	Pointer string
+	Message string
+}
+
+func (w LintWarning) String() string {
+	return fmt.Sprintf("[%s] %s: %s", w.Severity, w.Pointer, w.Message)
+}
+
+// formatTypes maps the formats of the OpenAPI specification to the types they can be used with.
+// Other formats are custom and can be used with any type.
+var formatTypes = map[string][]string{
+	"int32":     {"integer", "number"},
+	"int64":     {"integer", "number"},
+	"float":     {"number"},
+	"double":    {"number"},
+	"byte":      {"string"},
+	"binary":    {"string", "file"},
+	"date":      {"string"},
+	"date-time": {"string"},
+	"password":  {"string"},
+	"email":     {"string"},
+	"uri":       {"string"},
+	"uuid":      {"string"},
+	"hostname":  {"string"},
+	"ipv4":      {"string"},
+	"ipv6":      {"string"},
+}
+
+// LintSwagger returns the problems found in the definitions, parameters and responses of the swagger, sorted by pointer
+// and, for the same pointer, by decreasing severity and message.
+// It reports properties without description, enums with a single value, formats that do not match their type
+// and empty definitions.
+func LintSwagger(swagger *spec.Swagger) []LintWarning {
+	l := swaggerLinter{}
+	for name, def := range swagger.Definitions {
+		at := "/definitions/" + escapeJSONPointer(name)
+		if isEmptySchema(def) {
+			l.add(SeverityWarning, at, "definition is empty")
+		}
+		l.lintSchema(at, &def)
+	}
+	for path, item := range pathsOf(swagger) {
+		at := "/paths/" + escapeJSONPointer(path)
+		l.lintParameters(at+"/parameters", item.Parameters)
+		for method, op := range pathItemOperationsByMethod(item) {
+			opAt := at + "/" + strings.ToLower(method)
+			l.lintParameters(opAt+"/parameters", op.Parameters)
+			if op.Responses == nil {
+				continue
+			}
+			if op.Responses.Default != nil {
+				l.lintSchema(opAt+"/responses/default/schema", op.Responses.Default.Schema)
+			}
+			for code, rsp := range op.Responses.StatusCodeResponses {
+				l.lintSchema(opAt+"/responses/"+strconv.Itoa(code)+"/schema", rsp.Schema)
+			}
+		}
+	}
+	sort.Slice(l.warnings, func(i, j int) bool {
+		wi, wj := l.warnings[i], l.warnings[j]
+		if wi.Pointer != wj.Pointer {
+			return wi.Pointer < wj.Pointer
+		}
+		if wi.Severity != wj.Severity {
+			return wi.Severity > wj.Severity
+		}
+		return wi.Message < wj.Message
+	})
+	return l.warnings
+}
+
+type swaggerLinter struct {
+	warnings []LintWarning
+}
+
+func (l *swaggerLinter) add(severity Severity, pointer, format string, args ...interface{}) {
+	l.warnings = append(l.warnings, LintWarning{Severity: severity, Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *swaggerLinter) lintParameters(at string, params []spec.Parameter) {
+	for i, each := range params {
+		paramAt := at + "/" + strconv.Itoa(i)
+		if each.Schema != nil {
+			l.lintSchema(paramAt+"/schema", each.Schema)
+			continue
+		}
+		l.lintFormat(paramAt, each.Type, each.Format)
+		l.lintEnum(paramAt, each.Enum, each.Extensions)
+	}
+}
+
+func (l *swaggerLinter) lintSchema(at string, s *spec.Schema) {
+	if s == nil {
+		return
+	}
+	for _, each := range s.Type {
+		l.lintFormat(at, each, s.Format)
+	}
+	l.lintEnum(at, s.Enum, s.Extensions)
+	for name, prop := range s.Properties {
+		propAt := at + "/properties/" + escapeJSONPointer(name)
+		if prop.Description == "" && prop.Ref.String() == "" {
+			l.add(SeverityInfo, propAt, "property has no description")
+		}
+		l.lintSchema(propAt, &prop)
+	}
+	if s.Items != nil {
+		l.lintSchema(at+"/items", s.Items.Schema)
+		for i := range s.Items.Schemas {
+			l.lintSchema(at+"/items/"+strconv.Itoa(i), &s.Items.Schemas[i])
+		}
+	}
+	if s.AdditionalProperties != nil {
+		l.lintSchema(at+"/additionalProperties", s.AdditionalProperties.Schema)
+	}
+	for i := range s.AllOf {
+		l.lintSchema(at+"/allOf/"+strconv.Itoa(i), &s.AllOf[i])
+	}
+}
+
+func (l *swaggerLinter) lintFormat(at, typ, format string) {
+	types, ok := formatTypes[format]
+	if !ok || typ == "" {
+		return
+	}
+	if !containsString(types, typ) {
+		l.add(SeverityError, at, "format %s does not match type %s", format, typ)
+	}
+}
+
+func (l *swaggerLinter) lintEnum(at string, enum []interface{}, extensions spec.Extensions) {
+	if len(enum) != 1 {
+		return
+	}
+	// a constant is an enum with one value on purpose, see setConstValue
+	if isConst, _ := extensions.GetBool("x-const"); isConst {
+		return
+	}
+	l.add(SeverityWarning, at, "enum has a single value %v", enum[0])
+}
+
+// isEmptySchema returns true if the schema describes nothing more than an object.
+func isEmptySchema(s spec.Schema) bool {
+	if len(s.Type) > 1 || (len(s.Type) == 1 && s.Type[0] != "object") {
+		return false
+	}
+	return len(s.Properties) == 0 && s.AdditionalProperties == nil && s.Items == nil && s.Ref.String() == "" &&
+		len(s.AllOf) == 0 && len(s.AnyOf) == 0 && len(s.OneOf) == 0 && len(s.Enum) == 0
+}
+
+// escapeJSONPointer escapes a reference token of a JSON Pointer, see RFC 6901.
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,75 @@
+package restfulspec
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+)
+
+func TestLintSwagger(t *testing.T) {
+	user := spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: []string{"object"},
+		Properties: map[string]spec.Schema{
+			"name":    *spec.StringProperty().WithDescription("name of the user"),
+			"created": {SchemaProps: spec.SchemaProps{Type: []string{"integer"}, Format: "date-time", Description: "unix time"}},
+			"kind":    *spec.StringProperty().WithDescription("always user").WithEnum("user"),
+			"role":    *spec.StringProperty(),
+			"group":   *spec.RefProperty("#/definitions/Group"),
+		},
+	}}
+	constant := *spec.StringProperty().WithDescription("version").WithEnum("v1")
+	constant.AddExtension("x-const", true)
+	user.Properties["version"] = constant
+
+	swagger := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Definitions: spec.Definitions{
+			"User":  user,
+			"Group": {SchemaProps: spec.SchemaProps{Type: []string{"object"}}},
+		},
+		Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+			"/users/{id}": {PathItemProps: spec.PathItemProps{Get: &spec.Operation{OperationProps: spec.OperationProps{
+				Parameters: []spec.Parameter{*spec.PathParam("id").Typed("string", "uuid"), *spec.QueryParam("since").Typed("integer", "date")},
+				Responses: &spec.Responses{ResponsesProps: spec.ResponsesProps{StatusCodeResponses: map[int]spec.Response{
+					200: {ResponseProps: spec.ResponseProps{Schema: spec.ArrayProperty(spec.RefProperty("#/definitions/User"))}},
+				}}},
+			}}}},
+		}},
+	}}
+
+	warnings := LintSwagger(swagger)
+	expected := []LintWarning{
+		{SeverityWarning, "/definitions/Group", "definition is empty"},
+		{SeverityError, "/definitions/User/properties/created", "format date-time does not match type integer"},
+		{SeverityWarning, "/definitions/User/properties/kind", "enum has a single value user"},
+		{SeverityInfo, "/definitions/User/properties/role", "property has no description"},
+		{SeverityError, "/paths/~1users~1{id}/get/parameters/1", "format date does not match type integer"},
+	}
+	if got, want := len(warnings), len(expected); got != want {
+		t.Fatalf("got %d warnings want %d: %v", got, want, warnings)
+	}
+	for i, each := range expected {
+		if warnings[i] != each {
+			t.Errorf("got %v want %v", warnings[i], each)
+		}
+	}
+	if got, want := warnings[1].String(), "[error] /definitions/User/properties/created: format date-time does not match type integer"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestLintSwaggerOrderOfSamePointer(t *testing.T) {
+	swagger := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Definitions: spec.Definitions{
+		"Event": {SchemaProps: spec.SchemaProps{Type: []string{"object"}, Properties: map[string]spec.Schema{
+			"at": {SchemaProps: spec.SchemaProps{Type: []string{"integer"}, Format: "date", Enum: []interface{}{1}}},
+		}}},
+	}}}
+	warnings := LintSwagger(swagger)
+	if got, want := len(warnings), 3; got != want {
+		t.Fatalf("got %d warnings want %d: %v", got, want, warnings)
+	}
+	for i, want := range []Severity{SeverityError, SeverityWarning, SeverityInfo} {
+		if got := warnings[i].Severity; got != want {
+			t.Errorf("got %v want %v at %d: %v", got, want, i, warnings)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package restfulspec
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/emicklei/go-restful/v3/log"
+)
+
+// Logger receives the warnings of the package, e.g. about definitions that exceed the maximum depth.
+// The fields are alternating keys and values, such that structured loggers like zap, slog and logrus
+// are easily adapted.
+type Logger interface {
+	Warn(msg string, fields ...interface{})
+}
+
+// StderrLogger is a Logger that writes each warning as a line to os.Stderr.
+type StderrLogger struct{}
+
+// Warn writes the message and its fields as key=value pairs.
+func (StderrLogger) Warn(msg string, fields ...interface{}) {
+	fmt.Fprintln(os.Stderr, "[restful-openapi] "+formatLogMessage(msg, fields))
+}
+
+// NopLogger is a Logger that discards all warnings.
+type NopLogger struct{}
+
+// Warn does nothing.
+func (NopLogger) Warn(string, ...interface{}) {}
+
+// restfulLogger writes warnings to the logger of go-restful, see log.SetLogger.
+// It is used if the Config has no Logger.
+type restfulLogger struct{}
+
+func (restfulLogger) Warn(msg string, fields ...interface{}) {
+	log.Print("[restful-openapi] " + formatLogMessage(msg, fields))
+}
+
+// warn sends the message to the SlogLogger of the config, or else its Logger.
+// In StrictMode the message is an error of the build instead.
+func warn(cfg Config, msg string, fields ...interface{}) {
+	if cfg.diagnostics.record(cfg, msg, fields) {
+		return
+	}
+	if cfg.SlogLogger != nil {
+		cfg.SlogLogger.Warn(msg, fields...)
+		return
+	}
+	var logger Logger = restfulLogger{}
+	if cfg.Logger != nil {
+		logger = cfg.Logger
+	}
+	logger.Warn(msg, fields...)
+}
+
+// formatLogMessage returns the message followed by the fields as key=value pairs; string values are quoted.
+func formatLogMessage(msg string, fields []interface{}) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i < len(fields); i += 2 {
+		b.WriteString(" ")
+		if i+1 == len(fields) {
+			fmt.Fprintf(&b, "%v", fields[i])
+			break
+		}
+		if s, ok := fields[i+1].(string); ok {
+			fmt.Fprintf(&b, "%v=%q", fields[i], s)
+		} else {
+			fmt.Fprintf(&b, "%v=%v", fields[i], fields[i+1])
+		}
+	}
+	return b.String()
+}
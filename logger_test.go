@@ -0,0 +1,71 @@
+package restfulspec
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/go-openapi/spec"
+)
+
+type recordingWarnLogger struct {
+	warnings []string
+}
+
+func (l *recordingWarnLogger) Warn(msg string, fields ...interface{}) {
+	l.warnings = append(l.warnings, formatLogMessage(msg, fields))
+}
+
+func TestConfigLogger(t *testing.T) {
+	logger := new(recordingWarnLogger)
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/levels").To(dummy).Returns(200, "ok", Level1{}))
+	BuildSwagger(Config{
+		WebServices:        []*restful.WebService{ws},
+		MaxDefinitionDepth: 1,
+		XTagGroups:         []TagGroup{{Name: "Users", Tags: []string{"users"}}},
+		PostBuildSwaggerObjectHandler: func(s *spec.Swagger) {
+			s.Tags = nil
+		},
+		Logger: logger,
+	})
+	if got, want := len(logger.warnings), 2; got != want {
+		t.Fatalf("got %d warnings want %d: %v", got, want, logger.warnings)
+	}
+	if !strings.Contains(logger.warnings[0], `definition="restfulspec.Level2" maxDepth=1`) {
+		t.Errorf("got %v", logger.warnings[0])
+	}
+	if !strings.Contains(logger.warnings[1], `group="Users" tag="users"`) {
+		t.Errorf("got %v", logger.warnings[1])
+	}
+}
+
+func TestFormatLogMessage(t *testing.T) {
+	for _, each := range []struct {
+		fields []interface{}
+		want   string
+	}{
+		{nil, "message"},
+		{[]interface{}{"tag", "users", "depth", 2}, `message tag="users" depth=2`},
+		{[]interface{}{"odd"}, "message odd"},
+	} {
+		if got := formatLogMessage("message", each.fields); got != each.want {
+			t.Errorf("got %v want %v", got, each.want)
+		}
+	}
+}
+
+func TestConfigSlogLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	BuildSwagger(Config{
+		XTagGroups: []TagGroup{{Name: "Users", Tags: []string{"users"}}},
+		SlogLogger: slog.New(slog.NewTextHandler(buf, nil)),
+		// not used if SlogLogger is set
+		Logger: NopLogger{},
+	})
+	if !strings.Contains(buf.String(), `level=WARN msg="tag of x-tagGroups group is not defined in the swagger tags" group=Users tag=users`) {
+		t.Errorf("got %s", buf.String())
+	}
+}
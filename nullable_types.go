@@ -0,0 +1,78 @@
+package restfulspec
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/go-openapi/spec"
+)
+
+// nullableTypes maps the names, as package path and type name, of types that hold a value or null
+// to the type of that value.
+var nullableTypes = map[string]reflect.Type{
+	"database/sql.NullBool":    reflect.TypeOf(false),
+	"database/sql.NullByte":    reflect.TypeOf(byte(0)),
+	"database/sql.NullFloat64": reflect.TypeOf(float64(0)),
+	"database/sql.NullInt16":   reflect.TypeOf(int16(0)),
+	"database/sql.NullInt32":   reflect.TypeOf(int32(0)),
+	"database/sql.NullInt64":   reflect.TypeOf(int64(0)),
+	"database/sql.NullString":  reflect.TypeOf(""),
+	"database/sql.NullTime":    reflect.TypeOf(time.Time{}),
+}
+
+// pgtypePackages are the import paths of the pgtype packages of pgx, see Config.PgtypeMapping.
+var pgtypePackages = []string{
+	"github.com/jackc/pgtype",
+	"github.com/jackc/pgx/v5/pgtype",
+}
+
+// pgtypeSchemas maps the names of the most common pgtype types to the schema of their JSON value.
+var pgtypeSchemas = map[string]spec.Schema{
+	"Bool":        *spec.BoolProperty(),
+	"Date":        *spec.DateProperty(),
+	"Float4":      *spec.Float32Property(),
+	"Float8":      *spec.Float64Property(),
+	"Int2":        *spec.Int16Property(),
+	"Int4":        *spec.Int32Property(),
+	"Int8":        *spec.Int64Property(),
+	"Numeric":     {SchemaProps: spec.SchemaProps{Type: []string{"number"}}},
+	"Text":        *spec.StringProperty(),
+	"Timestamp":   *spec.DateTimeProperty(),
+	"Timestamptz": *spec.DateTimeProperty(),
+	"UUID":        *spec.StrFmtProperty("uuid"),
+	"Varchar":     *spec.StringProperty(),
+}
+
+// nullablePrimitive returns the nullable schema of the value of a nullable type such as sql.NullString,
+// or of the type it points to.
+func (b definitionBuilder) nullablePrimitive(t reflect.Type) (spec.Schema, bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	valueType, ok := nullableTypes[t.PkgPath()+"."+t.Name()]
+	if !ok {
+		return b.pgtypePrimitive(t)
+	}
+	valueName := keyFrom(valueType, b.Config)
+	s := spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:   []string{b.jsonSchemaType(valueName, valueType.Kind())},
+		Format: b.jsonSchemaFormat(valueName, valueType.Kind()),
+	}}
+	setNullable(b, &s)
+	return s, true
+}
+
+// pgtypePrimitive returns the nullable schema of a pgtype type if Config.PgtypeMapping is set.
+func (b definitionBuilder) pgtypePrimitive(t reflect.Type) (spec.Schema, bool) {
+	if !b.Config.PgtypeMapping || !containsString(pgtypePackages, t.PkgPath()) {
+		return spec.Schema{}, false
+	}
+	s, ok := pgtypeSchemas[t.Name()]
+	if !ok {
+		return spec.Schema{}, false
+	}
+	s.Type = append([]string(nil), s.Type...)
+	s.Extensions = nil
+	setNullable(b, &s)
+	return s, true
+}
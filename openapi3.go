@@ -0,0 +1,529 @@
+package restfulspec
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// BuildOpenAPI3 returns the OpenAPI 3 document of all services' API endpoints, see ConvertToOpenAPI3,
+// in the OpenAPIVersion of the config.
+func BuildOpenAPI3(config Config) (map[string]interface{}, error) {
+	swagger, err := BuildSwaggerWithError(config)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertToOpenAPI3(swagger, config.OpenAPIVersion)
+}
+
+// ConvertToOpenAPI3 returns the OpenAPI 3 document, as JSON values, of the Swagger object in the version,
+// OpenAPIVersion31 or else OpenAPIVersion3. Body and formData parameters become the requestBody of their operation
+// with a schema for each media type that it consumes, response schemas are in the content of each media type
+// that it produces and definitions, global parameters and global responses are in the components.
+// The Swagger object is not modified.
+func ConvertToOpenAPI3(swagger *spec.Swagger, version string) (map[string]interface{}, error) {
+	data, err := json.Marshal(swagger)
+	if err != nil {
+		return nil, err
+	}
+	var source map[string]interface{}
+	if err := json.Unmarshal(data, &source); err != nil {
+		return nil, err
+	}
+	c := openAPI3Converter{
+		version:  version,
+		consumes: mediaTypes(source["consumes"]),
+		produces: mediaTypes(source["produces"]),
+	}
+	doc := c.document(source)
+	c.rewrite(doc)
+	return doc, nil
+}
+
+// openAPI3Converter converts the JSON values of a Swagger document.
+type openAPI3Converter struct {
+	version string
+	// media types of the document, used by operations without their own
+	consumes, produces []string
+}
+
+func (c openAPI3Converter) document(source map[string]interface{}) map[string]interface{} {
+	doc := map[string]interface{}{"openapi": "3.0.3"}
+	if c.version == OpenAPIVersion31 {
+		doc["openapi"] = "3.1.0"
+	}
+	for _, key := range []string{"info", "tags", "security", "externalDocs"} {
+		if value, ok := source[key]; ok {
+			doc[key] = value
+		}
+	}
+	copyExtensions(doc, source)
+	if servers := servers(source); len(servers) > 0 {
+		doc["servers"] = servers
+	}
+	components := map[string]interface{}{}
+	if definitions, ok := source["definitions"].(map[string]interface{}); ok && len(definitions) > 0 {
+		components["schemas"] = definitions
+	}
+	if params, ok := source["parameters"].(map[string]interface{}); ok {
+		parameters, bodies := map[string]interface{}{}, map[string]interface{}{}
+		for name, each := range params {
+			param, _ := each.(map[string]interface{})
+			if param["in"] == "body" {
+				bodies[name] = c.requestBody([]interface{}{param}, c.consumes)
+			} else {
+				parameters[name] = convertParameter(param)
+			}
+		}
+		if len(parameters) > 0 {
+			components["parameters"] = parameters
+		}
+		if len(bodies) > 0 {
+			components["requestBodies"] = bodies
+		}
+	}
+	if responses, ok := source["responses"].(map[string]interface{}); ok && len(responses) > 0 {
+		converted := map[string]interface{}{}
+		for name, each := range responses {
+			converted[name] = c.response(each, c.produces)
+		}
+		components["responses"] = converted
+	}
+	if schemes, ok := source["securityDefinitions"].(map[string]interface{}); ok && len(schemes) > 0 {
+		converted := map[string]interface{}{}
+		for name, each := range schemes {
+			converted[name] = securityScheme(each)
+		}
+		components["securitySchemes"] = converted
+	}
+	if len(components) > 0 {
+		doc["components"] = components
+	}
+	paths := map[string]interface{}{}
+	if items, ok := source["paths"].(map[string]interface{}); ok {
+		for path, each := range items {
+			paths[path] = c.pathItem(each)
+		}
+	}
+	doc["paths"] = paths
+	return doc
+}
+
+// servers returns the server objects of the schemes, host and basePath of the Swagger document.
+func servers(source map[string]interface{}) []interface{} {
+	host, _ := source["host"].(string)
+	basePath, _ := source["basePath"].(string)
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+	schemes := mediaTypes(source["schemes"])
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+	var list []interface{}
+	for _, scheme := range schemes {
+		list = append(list, map[string]interface{}{"url": scheme + "://" + host + basePath})
+	}
+	return list
+}
+
+func (c openAPI3Converter) pathItem(value interface{}) map[string]interface{} {
+	source, _ := value.(map[string]interface{})
+	item := map[string]interface{}{}
+	for key, each := range source {
+		switch key {
+		case "parameters":
+			if params := convertParameters(each); len(params) > 0 {
+				item[key] = params
+			}
+		case "get", "put", "post", "delete", "options", "head", "patch":
+			item[key] = c.operation(each)
+		default:
+			// $ref and extensions
+			item[key] = each
+		}
+	}
+	return item
+}
+
+func (c openAPI3Converter) operation(value interface{}) map[string]interface{} {
+	source, _ := value.(map[string]interface{})
+	op := map[string]interface{}{}
+	for _, key := range []string{"tags", "summary", "description", "externalDocs", "operationId", "deprecated", "security"} {
+		if each, ok := source[key]; ok {
+			op[key] = each
+		}
+	}
+	copyExtensions(op, source)
+	consumes, produces := c.consumes, c.produces
+	if list := mediaTypes(source["consumes"]); len(list) > 0 {
+		consumes = list
+	}
+	if list := mediaTypes(source["produces"]); len(list) > 0 {
+		produces = list
+	}
+	params, _ := source["parameters"].([]interface{})
+	var body []interface{}
+	for _, each := range params {
+		if param, _ := each.(map[string]interface{}); param["in"] == "body" || param["in"] == "formData" {
+			body = append(body, param)
+		}
+	}
+	if converted := convertParameters(params); len(converted) > 0 {
+		op["parameters"] = converted
+	}
+	if len(body) > 0 {
+		op["requestBody"] = c.requestBody(body, consumes)
+	}
+	responses := map[string]interface{}{}
+	if list, ok := source["responses"].(map[string]interface{}); ok {
+		for code, each := range list {
+			responses[code] = c.response(each, produces)
+		}
+	}
+	op["responses"] = responses
+	return op
+}
+
+// requestBody returns the request body of the body parameter or the formData parameters,
+// with a media type object for each of the consumes.
+func (c openAPI3Converter) requestBody(params []interface{}, consumes []string) map[string]interface{} {
+	body := map[string]interface{}{}
+	var schema interface{}
+	if first, _ := params[0].(map[string]interface{}); first["in"] != "formData" {
+		schema = first["schema"]
+		for _, key := range []string{"description", "required"} {
+			if each, ok := first[key]; ok {
+				body[key] = each
+			}
+		}
+		copyExtensions(body, first)
+		if len(consumes) == 0 {
+			consumes = []string{"application/json"}
+		}
+	} else {
+		schema = formDataSchema(params)
+		consumes = formMediaTypes(consumes, params)
+	}
+	content := map[string]interface{}{}
+	for _, each := range consumes {
+		media := map[string]interface{}{}
+		if schema != nil {
+			media["schema"] = schema
+		}
+		content[each] = media
+	}
+	body["content"] = content
+	return body
+}
+
+// formDataSchema returns the object schema with a property for each formData parameter.
+func formDataSchema(params []interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []interface{}
+	for _, each := range params {
+		param, _ := each.(map[string]interface{})
+		name, _ := param["name"].(string)
+		property := parameterSchema(param)
+		if param["type"] == "file" {
+			property = map[string]interface{}{"type": "string", "format": "binary"}
+		}
+		if description, ok := param["description"]; ok {
+			property["description"] = description
+		}
+		properties[name] = property
+		if param["required"] == true {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// formMediaTypes returns the form media types of the consumes or else the media type for the formData parameters:
+// multipart/form-data for files, application/x-www-form-urlencoded otherwise.
+func formMediaTypes(consumes []string, params []interface{}) []string {
+	var forms []string
+	for _, each := range consumes {
+		if each == "multipart/form-data" || each == "application/x-www-form-urlencoded" {
+			forms = append(forms, each)
+		}
+	}
+	if len(forms) > 0 {
+		return forms
+	}
+	for _, each := range params {
+		if param, _ := each.(map[string]interface{}); param["type"] == "file" {
+			return []string{"multipart/form-data"}
+		}
+	}
+	return []string{"application/x-www-form-urlencoded"}
+}
+
+// response returns the response with its schema and examples in a media type object for each of the produces.
+func (c openAPI3Converter) response(value interface{}, produces []string) map[string]interface{} {
+	source, _ := value.(map[string]interface{})
+	if ref, ok := source["$ref"]; ok {
+		return map[string]interface{}{"$ref": ref}
+	}
+	rsp := map[string]interface{}{"description": source["description"]}
+	if rsp["description"] == nil {
+		rsp["description"] = ""
+	}
+	copyExtensions(rsp, source)
+	if links, ok := rsp["x-links"]; ok {
+		delete(rsp, "x-links")
+		rsp["links"] = links
+	}
+	if headers, ok := source["headers"].(map[string]interface{}); ok && len(headers) > 0 {
+		converted := map[string]interface{}{}
+		for name, each := range headers {
+			header, _ := each.(map[string]interface{})
+			h := map[string]interface{}{"schema": parameterSchema(header)}
+			if description, ok := header["description"]; ok {
+				h["description"] = description
+			}
+			converted[name] = h
+		}
+		rsp["headers"] = converted
+	}
+	schema, hasSchema := source["schema"]
+	examples, _ := source["examples"].(map[string]interface{})
+	if !hasSchema && len(examples) == 0 {
+		return rsp
+	}
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+	content := map[string]interface{}{}
+	for _, each := range produces {
+		media := map[string]interface{}{}
+		if hasSchema {
+			media["schema"] = schema
+		}
+		if example, ok := examples[each]; ok {
+			media["example"] = example
+		}
+		content[each] = media
+	}
+	rsp["content"] = content
+	return rsp
+}
+
+// convertParameters returns the parameters that are not body or formData parameters in OpenAPI 3.
+func convertParameters(value interface{}) []interface{} {
+	params, _ := value.([]interface{})
+	var converted []interface{}
+	for _, each := range params {
+		param, _ := each.(map[string]interface{})
+		if param["in"] == "body" || param["in"] == "formData" {
+			continue
+		}
+		converted = append(converted, convertParameter(param))
+	}
+	return converted
+}
+
+// convertParameter moves the type keywords of a parameter into its schema and its collectionFormat to style.
+func convertParameter(param map[string]interface{}) map[string]interface{} {
+	if ref, ok := param["$ref"]; ok {
+		return map[string]interface{}{"$ref": ref}
+	}
+	converted := map[string]interface{}{"schema": parameterSchema(param)}
+	for _, key := range []string{"name", "in", "description", "required", "allowEmptyValue"} {
+		if each, ok := param[key]; ok {
+			converted[key] = each
+		}
+	}
+	copyExtensions(converted, param)
+	switch param["collectionFormat"] {
+	case "csv":
+		converted["explode"] = false
+		if param["in"] == "query" {
+			converted["style"] = "form"
+		}
+	case "multi":
+		converted["style"] = "form"
+		converted["explode"] = true
+	case "ssv":
+		converted["style"] = "spaceDelimited"
+	case "pipes":
+		converted["style"] = "pipeDelimited"
+	}
+	return converted
+}
+
+// schemaKeywords are the keywords of non-body parameters, headers and items that are schema keywords in OpenAPI 3.
+var schemaKeywords = []string{"type", "format", "default", "enum", "pattern", "maximum", "exclusiveMaximum", "minimum",
+	"exclusiveMinimum", "maxLength", "minLength", "maxItems", "minItems", "uniqueItems", "multipleOf"}
+
+// parameterSchema returns the schema of the type keywords of a parameter, header or items.
+func parameterSchema(param map[string]interface{}) map[string]interface{} {
+	schema := map[string]interface{}{}
+	for _, key := range schemaKeywords {
+		if each, ok := param[key]; ok {
+			schema[key] = each
+		}
+	}
+	if items, ok := param["items"].(map[string]interface{}); ok {
+		schema["items"] = parameterSchema(items)
+	}
+	return schema
+}
+
+// securityScheme returns the security scheme of a security definition.
+func securityScheme(value interface{}) map[string]interface{} {
+	source, _ := value.(map[string]interface{})
+	scheme := map[string]interface{}{}
+	for _, key := range []string{"description", "name", "in"} {
+		if each, ok := source[key]; ok {
+			scheme[key] = each
+		}
+	}
+	copyExtensions(scheme, source)
+	switch source["type"] {
+	case "basic":
+		scheme["type"] = "http"
+		scheme["scheme"] = "basic"
+	case "oauth2":
+		scheme["type"] = "oauth2"
+		flow := map[string]interface{}{"scopes": map[string]interface{}{}}
+		if scopes, ok := source["scopes"]; ok {
+			flow["scopes"] = scopes
+		}
+		for _, key := range []string{"authorizationUrl", "tokenUrl"} {
+			if each, ok := source[key]; ok {
+				flow[key] = each
+			}
+		}
+		names := map[string]string{"implicit": "implicit", "password": "password", "application": "clientCredentials", "accessCode": "authorizationCode"}
+		flowName, _ := source["flow"].(string)
+		scheme["flows"] = map[string]interface{}{names[flowName]: flow}
+	default:
+		scheme["type"] = source["type"]
+	}
+	return scheme
+}
+
+// rewrite replaces the $ref of definitions, parameters and responses by those of the components,
+// x-nullable by the nullable keyword of the version and, for OpenAPI 3.1, the boolean exclusiveMaximum
+// and exclusiveMinimum by the numeric ones of JSON Schema.
+func (c openAPI3Converter) rewrite(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			v["$ref"] = componentRef(ref)
+		}
+		if nullable, ok := v["x-nullable"].(bool); ok {
+			delete(v, "x-nullable")
+			if nullable {
+				v["nullable"] = true
+			}
+		}
+		if c.version == OpenAPIVersion31 {
+			if v["nullable"] == true {
+				delete(v, "nullable")
+				allowNull(v)
+			}
+			exclusiveBound(v, "exclusiveMaximum", "maximum")
+			exclusiveBound(v, "exclusiveMinimum", "minimum")
+		}
+		for _, each := range v {
+			c.rewrite(each)
+		}
+	case []interface{}:
+		for _, each := range v {
+			c.rewrite(each)
+		}
+	}
+}
+
+// allowNull makes the schema accept null in OpenAPI 3.1, which has no nullable keyword: by the null type
+// if it has a type, or else by an anyOf or oneOf alternative. A schema without a type or subschemas accepts null already.
+func allowNull(schema map[string]interface{}) {
+	null := map[string]interface{}{"type": "null"}
+	switch t := schema["type"].(type) {
+	case string:
+		schema["type"] = []interface{}{t, "null"}
+		return
+	case []interface{}:
+		for _, each := range t {
+			if each == "null" {
+				return
+			}
+		}
+		schema["type"] = append(t, "null")
+		return
+	}
+	if ref, ok := schema["$ref"]; ok {
+		delete(schema, "$ref")
+		schema["anyOf"] = []interface{}{map[string]interface{}{"$ref": ref}, null}
+		return
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		if list, ok := schema[key].([]interface{}); ok {
+			schema[key] = append(list, null)
+			return
+		}
+	}
+	if allOf, ok := schema["allOf"]; ok {
+		delete(schema, "allOf")
+		schema["anyOf"] = []interface{}{map[string]interface{}{"allOf": allOf}, null}
+	}
+}
+
+// exclusiveBound replaces the boolean exclusive keyword of Swagger 2.0 and OpenAPI 3.0 by the numeric one of
+// OpenAPI 3.1, which is the value of the bound keyword if true.
+func exclusiveBound(schema map[string]interface{}, exclusive, bound string) {
+	flag, ok := schema[exclusive].(bool)
+	if !ok {
+		return
+	}
+	delete(schema, exclusive)
+	if value, ok := schema[bound]; ok && flag {
+		schema[exclusive] = value
+		delete(schema, bound)
+	}
+}
+
+// componentRef returns the reference in the components of a reference in a Swagger document.
+func componentRef(ref string) string {
+	for from, to := range map[string]string{
+		definitionRoot:  "#/components/schemas/",
+		"#/parameters/": "#/components/parameters/",
+		"#/responses/":  "#/components/responses/",
+	} {
+		if strings.HasPrefix(ref, from) {
+			return to + strings.TrimPrefix(ref, from)
+		}
+	}
+	return ref
+}
+
+// mediaTypes returns the strings of a JSON array.
+func mediaTypes(value interface{}) []string {
+	list, _ := value.([]interface{})
+	var types []string
+	for _, each := range list {
+		if s, ok := each.(string); ok {
+			types = append(types, s)
+		}
+	}
+	return types
+}
+
+// copyExtensions copies the x- extensions of the source to the target.
+func copyExtensions(target, source map[string]interface{}) {
+	for key, value := range source {
+		if strings.HasPrefix(strings.ToLower(key), ExtensionPrefix) {
+			target[key] = value
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package restfulspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/go-openapi/spec"
+)
+
+type Note struct {
+	Text   string
+	Author *string
+	Tags   []NoteTag
+}
+
+type NoteTag struct {
+	Name string
+}
+
+func noteWebService() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path("/notes").Produces(restful.MIME_JSON)
+	ws.Route(ws.POST("").To(dummy).Consumes(restful.MIME_JSON).Reads(Note{}, "the note").Returns(201, "created", Note{}))
+	ws.Route(ws.PUT("/{id}").To(dummy).Consumes(restful.MIME_XML).
+		Param(ws.PathParameter("id", "identifier")).
+		Param(ws.QueryParameter("tag", "tags").AllowMultiple(true).CollectionFormat(restful.CollectionFormatMulti)).
+		Reads(Note{}).Returns(200, "updated", nil))
+	ws.Route(ws.POST("/{id}/attachments").To(dummy).Consumes("multipart/form-data").
+		Param(ws.PathParameter("id", "identifier")).
+		Param(ws.FormParameter("file", "the attachment").DataType("file").Required(true)).
+		Param(ws.FormParameter("title", "title of the attachment")).
+		Returns(204, "attached", nil))
+	return ws
+}
+
+// mediaTypesOf returns the sorted media types of the content of a request body or response.
+func mediaTypesOf(value interface{}) string {
+	m, _ := value.(map[string]interface{})
+	content, _ := m["content"].(map[string]interface{})
+	keys := make([]string, 0, len(content))
+	for key := range content {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return fmt.Sprint(keys)
+}
+
+// lookup returns the value at the path of keys in the JSON values.
+func lookup(value interface{}, keys ...string) interface{} {
+	for _, each := range keys {
+		m, _ := value.(map[string]interface{})
+		value = m[each]
+	}
+	return value
+}
+
+func TestConvertToOpenAPI3(t *testing.T) {
+	doc, err := BuildOpenAPI3(Config{WebServices: []*restful.WebService{noteWebService()}, Host: "api.example.com", BasePath: "/v1", Schemes: []string{"https"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := doc["openapi"], "3.0.3"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := fmt.Sprint(doc["servers"]), "[map[url:https://api.example.com/v1]]"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	post := lookup(doc, "paths", "/notes", "post")
+	body := lookup(post, "requestBody")
+	if got, want := mediaTypesOf(body), "[application/json]"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := lookup(body, "content", "application/json", "schema", "$ref"), "#/components/schemas/restfulspec.Note"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := lookup(body, "description"), "the note"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if params := lookup(post, "parameters"); params != nil {
+		t.Errorf("body parameter should not be a parameter: %v", params)
+	}
+	created := lookup(post, "responses", "201")
+	if got, want := lookup(created, "content", "application/json", "schema", "$ref"), "#/components/schemas/restfulspec.Note"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	put := lookup(doc, "paths", "/notes/{id}", "put")
+	if got, want := mediaTypesOf(lookup(put, "requestBody")), "[application/xml]"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	params, _ := lookup(put, "parameters").([]interface{})
+	if got, want := len(params), 2; got != want {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	for _, each := range params {
+		if lookup(each, "name") == "tag" {
+			if got, want := fmt.Sprintf("%v %v %v", lookup(each, "style"), lookup(each, "explode"), lookup(each, "schema", "type")), "form true array"; got != want {
+				t.Errorf("got %v want %v", got, want)
+			}
+		}
+	}
+	if got, want := mediaTypesOf(lookup(put, "responses", "200")), "[]"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	attach := lookup(doc, "paths", "/notes/{id}/attachments", "post")
+	form := lookup(attach, "requestBody")
+	if got, want := mediaTypesOf(form), "[multipart/form-data]"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	schema := lookup(form, "content", "multipart/form-data", "schema")
+	if got, want := fmt.Sprint(lookup(schema, "properties", "file")), "map[description:the attachment format:binary type:string]"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := fmt.Sprint(lookup(schema, "required")), "[file]"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	tags := lookup(doc, "components", "schemas", "restfulspec.Note", "properties", "Tags", "items", "$ref")
+	if got, want := tags, "#/components/schemas/restfulspec.NoteTag"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	data, _ := json.Marshal(doc)
+	if strings.Contains(string(data), "#/definitions/") || strings.Contains(string(data), `"in":"body"`) {
+		t.Errorf("got %s", data)
+	}
+}
+
+func TestConvertToOpenAPI3Nullable(t *testing.T) {
+	for version, want := range map[string]string{
+		OpenAPIVersion3:  "map[nullable:true type:string]",
+		OpenAPIVersion31: "map[type:[string null]]",
+	} {
+		doc, err := BuildOpenAPI3(Config{WebServices: []*restful.WebService{noteWebService()}, OpenAPIVersion: version})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fmt.Sprint(lookup(doc, "components", "schemas", "restfulspec.Note", "properties", "Author")); got != want {
+			t.Errorf("%s: got %v want %v", version, got, want)
+		}
+	}
+	// the Swagger object keeps x-nullable
+	doc, _ := ConvertToOpenAPI3(BuildSwagger(Config{WebServices: []*restful.WebService{noteWebService()}}), OpenAPIVersion3)
+	if got, want := fmt.Sprint(lookup(doc, "components", "schemas", "restfulspec.Note", "properties", "Author")), "map[nullable:true type:string]"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestConvertToOpenAPI31Schemas(t *testing.T) {
+	var swagger spec.Swagger
+	if err := json.Unmarshal([]byte(`{
+	  "swagger": "2.0",
+	  "paths": {"/notes": {"post": {"responses": {"201": {"description": "created",
+	    "schema": {"$ref": "#/definitions/Note"},
+	    "x-links": {"GetNote": {"operationId": "getNote", "parameters": {"id": "$response.body#/id"}}}}}}}},
+	  "definitions": {"Note": {"properties": {
+	    "author": {"$ref": "#/definitions/Author", "x-nullable": true},
+	    "editor": {"allOf": [{"$ref": "#/definitions/Author"}], "x-nullable": true},
+	    "labels": {"type": ["string", "integer"], "x-nullable": true},
+	    "any": {"x-nullable": true},
+	    "rating": {"type": "integer", "maximum": 10, "exclusiveMaximum": true, "minimum": 1, "exclusiveMinimum": false}
+	  }}, "Author": {"type": "object"}}
+	}`), &swagger); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := ConvertToOpenAPI3(&swagger, OpenAPIVersion31)
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := lookup(doc, "components", "schemas", "Note", "properties")
+	for name, want := range map[string]string{
+		"author": "map[anyOf:[map[$ref:#/components/schemas/Author] map[type:null]]]",
+		"editor": "map[anyOf:[map[allOf:[map[$ref:#/components/schemas/Author]]] map[type:null]]]",
+		"labels": "map[type:[string integer null]]",
+		"any":    "map[]",
+		"rating": "map[exclusiveMaximum:10 minimum:1 type:integer]",
+	} {
+		if got := fmt.Sprint(lookup(props, name)); got != want {
+			t.Errorf("%s: got %v want %v", name, got, want)
+		}
+	}
+	created := lookup(doc, "paths", "/notes", "post", "responses", "201")
+	if got, want := fmt.Sprint(lookup(created, "links", "GetNote")), "map[operationId:getNote parameters:map[id:$response.body#/id]]"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if _, ok := created.(map[string]interface{})["x-links"]; ok {
+		t.Errorf("x-links is kept in %v", created)
+	}
+
+	// OpenAPI 3.0 keeps the nullable keyword and the boolean exclusive bounds
+	doc, _ = ConvertToOpenAPI3(&swagger, OpenAPIVersion3)
+	props = lookup(doc, "components", "schemas", "Note", "properties")
+	if got, want := fmt.Sprint(lookup(props, "author", "nullable")), "true"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := fmt.Sprint(lookup(props, "rating", "exclusiveMaximum")), "true"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestOpenAPIServiceServesOpenAPI3(t *testing.T) {
+	container := restful.NewContainer()
+	container.Add(NewOpenAPIService(Config{WebServices: []*restful.WebService{noteWebService()}, APIPath: "/openapi.json", OpenAPIVersion: OpenAPIVersion3}))
+	rec := httptest.NewRecorder()
+	container.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	var doc map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := doc["openapi"], "3.0.3"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if _, ok := doc["swagger"]; ok {
+		t.Errorf("got %s", rec.Body.String())
+	}
+}
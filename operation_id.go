@@ -0,0 +1,97 @@
+package restfulspec
+
+import (
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	restful "github.com/emicklei/go-restful/v3"
+)
+
+// anonymousFunction matches the names that Go gives to function literals, e.g. func1.
+var anonymousFunction = regexp.MustCompile(`^func\d+$`)
+
+// operationID returns the operation ID of the route, see Config.RouteToOperationIDFunc
+// and Config.DisableOperationIDFromHandler.
+func operationID(r restful.Route, cfg Config) string {
+	idFunc := cfg.RouteToOperationIDFunc
+	if idFunc == nil && !cfg.DisableOperationIDFromHandler {
+		idFunc = OperationIDFromHandler
+	}
+	if idFunc != nil {
+		if id := idFunc(r); id != "" {
+			return id
+		}
+	}
+	return r.Operation
+}
+
+// OperationIDFromHandler returns an operation ID derived from the name of the function of the route,
+// in camelCase and with the HTTP method as suffix, e.g. findUserGet for the method FindUser of a
+// UserResource that handles GET requests. Function literals are named after the function they are in.
+// It is used unless Config.RouteToOperationIDFunc is set or Config.DisableOperationIDFromHandler is true.
+// It returns an empty string if the route has no function
+// or an Operation other than the one go-restful derives from the function.
+func OperationIDFromHandler(r restful.Route) string {
+	if !hasDefaultOperation(r) {
+		return ""
+	}
+	name := handlerName(r.Function)
+	if name == "" {
+		return ""
+	}
+	first, size := utf8.DecodeRuneInString(name)
+	method := strings.ToLower(r.Method)
+	if method != "" {
+		method = strings.ToUpper(method[:1]) + method[1:]
+	}
+	return string(unicode.ToLower(first)) + name[size:] + method
+}
+
+// handlerName returns the name of the function without package, receiver and function literal suffixes.
+func handlerName(f restful.RouteFunction) string {
+	// example.com/pkg.(*Resource).Method or example.com/pkg.Function.func1
+	full := handlerFullName(f)
+	segments := strings.Split(full[strings.LastIndex(full, "/")+1:], ".")
+	for i := len(segments) - 1; i > 0; i-- {
+		each := segments[i]
+		if each == "" || anonymousFunction.MatchString(each) || strings.HasPrefix(each, "(") {
+			continue
+		}
+		return each
+	}
+	return ""
+}
+
+// handlerFullName returns the name of the function qualified by the import path of its package,
+// e.g. example.com/pkg.(*Resource).Method.
+// It returns an empty string if there is no function.
+func handlerFullName(f restful.RouteFunction) string {
+	if f == nil {
+		return ""
+	}
+	fun := runtime.FuncForPC(reflect.ValueOf(f).Pointer())
+	if fun == nil {
+		return ""
+	}
+	// method values are named after their method with a -fm suffix
+	return strings.TrimSuffix(fun.Name(), "-fm")
+}
+
+// hasDefaultOperation returns true if the operation of the route is the one that go-restful derives
+// from its function because none was set with RouteBuilder.Operation.
+func hasDefaultOperation(r restful.Route) bool {
+	full := handlerFullName(r.Function)
+	if full == "" {
+		return false
+	}
+	last := full[strings.LastIndex(full, ".")+1:]
+	if last == r.Operation {
+		return true
+	}
+	// go-restful numbers function literals itself
+	return anonymousFunction.MatchString(last) && anonymousFunction.MatchString(r.Operation)
+}
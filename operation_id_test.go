@@ -0,0 +1,115 @@
+package restfulspec
+
+import (
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+)
+
+type accountResource struct{}
+
+func (accountResource) FindAccount(*restful.Request, *restful.Response) {}
+
+func (*accountResource) UpdateAccount(*restful.Request, *restful.Response) {}
+
+func TestOperationIDFromHandler(t *testing.T) {
+	resource := new(accountResource)
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/accounts/{id}").To(resource.FindAccount))
+	ws.Route(ws.PUT("/accounts/{id}").To(resource.UpdateAccount))
+	ws.Route(ws.DELETE("/accounts/{id}").To(dummy))
+	ws.Route(ws.POST("/accounts").To(func(*restful.Request, *restful.Response) {}))
+	ws.Route(ws.PATCH("/accounts/{id}").To(dummy).Operation("patchAccount"))
+
+	// derived by default
+	cfg := Config{WebServices: []*restful.WebService{ws}}
+	item := BuildSwagger(cfg).Paths.Paths["/accounts/{id}"]
+	for _, each := range []struct{ got, want string }{
+		{item.Get.ID, "findAccountGet"},
+		{item.Put.ID, "updateAccountPut"},
+		{item.Delete.ID, "dummyDelete"},
+		{item.Patch.ID, "patchAccount"},
+	} {
+		if each.got != each.want {
+			t.Errorf("got %v want %v", each.got, each.want)
+		}
+	}
+	post := BuildSwagger(cfg).Paths.Paths["/accounts"].Post
+	if got, want := post.ID, "testOperationIDFromHandlerPost"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestDisableOperationIDFromHandler(t *testing.T) {
+	resource := new(accountResource)
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/accounts/{id}").To(resource.FindAccount))
+	ws.Route(ws.PUT("/accounts/{id}").To(resource.UpdateAccount).Operation("updateAccount"))
+
+	item := BuildSwagger(Config{WebServices: []*restful.WebService{ws}, DisableOperationIDFromHandler: true}).Paths.Paths["/accounts/{id}"]
+	if got, want := item.Get.ID, "FindAccount"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := item.Put.ID, "updateAccount"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestRouteToOperationIDFunc(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/accounts").To(dummy))
+	ws.Route(ws.POST("/accounts").To(dummy).Operation("createAccount"))
+
+	swagger := BuildSwagger(Config{
+		WebServices: []*restful.WebService{ws},
+		RouteToOperationIDFunc: func(r restful.Route) string {
+			if r.Method == "GET" {
+				return "listAccounts"
+			}
+			return ""
+		},
+	})
+	item := swagger.Paths.Paths["/accounts"]
+	if got, want := item.Get.ID, "listAccounts"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := item.Post.ID, "createAccount"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestEmitHandlerExtension(t *testing.T) {
+	resource := new(accountResource)
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/accounts/{id}").To(resource.FindAccount))
+	ws.Route(ws.PUT("/accounts/{id}").To(resource.UpdateAccount))
+	ws.Route(ws.DELETE("/accounts/{id}").To(dummy).Operation("deleteAccount"))
+	ws.Route(ws.POST("/accounts").To(func(*restful.Request, *restful.Response) {}))
+
+	swagger := BuildSwagger(Config{WebServices: []*restful.WebService{ws}, EmitHandlerExtension: true})
+	for path, item := range swagger.Paths.Paths {
+		for method, op := range pathItemOperationsByMethod(item) {
+			if name, _ := op.Extensions.GetString("x-go-handler"); name == "" {
+				t.Errorf("%s %s: missing x-go-handler", method, path)
+			}
+		}
+	}
+	item := swagger.Paths.Paths["/accounts/{id}"]
+	for _, each := range []struct {
+		ext  map[string]interface{}
+		want string
+	}{
+		{item.Get.Extensions, "github.com/emicklei/go-restful-openapi/v2.accountResource.FindAccount"},
+		{item.Put.Extensions, "github.com/emicklei/go-restful-openapi/v2.(*accountResource).UpdateAccount"},
+		{item.Delete.Extensions, "github.com/emicklei/go-restful-openapi/v2.dummy"},
+	} {
+		if got := each.ext["x-go-handler"]; got != each.want {
+			t.Errorf("got %v want %v", got, each.want)
+		}
+	}
+
+	swagger = BuildSwagger(Config{WebServices: []*restful.WebService{ws}})
+	if _, ok := swagger.Paths.Paths["/accounts"].Post.Extensions["x-go-handler"]; ok {
+		t.Error("extension should not be emitted by default")
+	}
+}
@@ -22,14 +22,38 @@ func setDescription(prop *spec.Schema, field reflect.StructField) {
 	}
 }
 
+// setFieldDescription sets the description of the FieldDescriptionFunc if the field has no description tag.
+func setFieldDescription(b definitionBuilder, prop *spec.Schema, field reflect.StructField) {
+	if b.Config.FieldDescriptionFunc == nil || field.Tag.Get("description") != "" {
+		return
+	}
+	if description := b.Config.FieldDescriptionFunc(field); description != "" {
+		prop.Description = description
+	}
+}
+
 func setDefaultValue(prop *spec.Schema, field reflect.StructField) {
 	if tag := field.Tag.Get("default"); tag != "" {
 		prop.Default = stringAutoType(tag)
 	}
 }
 
+func setConstValue(prop *spec.Schema, field reflect.StructField) {
+	if tag, ok := field.Tag.Lookup("const"); ok {
+		prop.Enum = []interface{}{stringAutoType(tag)}
+		initPropExtensions(&prop.Extensions)
+		prop.Extensions["x-const"] = true
+	}
+}
+
+// setIsNullableValue reads the x-nullable tag of the field, or the nullable tag of OpenAPI 3 users.
+// The resulting x-nullable extension is represented according to Config.NullableStrategy, see applyNullableStrategy.
 func setIsNullableValue(prop *spec.Schema, field reflect.StructField) {
-	if tag := field.Tag.Get("x-nullable"); tag != "" {
+	tag := field.Tag.Get("x-nullable")
+	if tag == "" {
+		tag = field.Tag.Get("nullable")
+	}
+	if tag != "" {
 		initPropExtensions(&prop.Extensions)
 
 		value, err := strconv.ParseBool(tag)
@@ -46,6 +70,31 @@ func setGoNameValue(prop *spec.Schema, field reflect.StructField) {
 	}
 }
 
+// setKubernetesExtensions sets the x-kubernetes- extensions of CustomResourceDefinition schemas
+// from the k8s_list_type, k8s_list_map_keys and k8s_preserve_unknown_fields tags if Config.KubernetesExtensionMode is set.
+func setKubernetesExtensions(b definitionBuilder, prop *spec.Schema, field reflect.StructField) {
+	if !b.Config.KubernetesExtensionMode {
+		return
+	}
+	if tag := field.Tag.Get("k8s_list_type"); tag != "" {
+		initPropExtensions(&prop.Extensions)
+		prop.Extensions["x-kubernetes-list-type"] = tag
+	}
+	if tag := field.Tag.Get("k8s_list_map_keys"); tag != "" {
+		var keys []string
+		for _, each := range strings.Split(tag, ",") {
+			keys = append(keys, strings.TrimSpace(each))
+		}
+		initPropExtensions(&prop.Extensions)
+		prop.Extensions["x-kubernetes-list-map-keys"] = keys
+	}
+	if tag := field.Tag.Get("k8s_preserve_unknown_fields"); tag != "" {
+		value, err := strconv.ParseBool(tag)
+		initPropExtensions(&prop.Extensions)
+		prop.Extensions["x-kubernetes-preserve-unknown-fields"] = value && err == nil
+	}
+}
+
 type EnumItem struct {
 	name  string
 	value int32
@@ -90,6 +139,9 @@ func setEnumValues(b definitionBuilder, prop *spec.Schema, field reflect.StructF
 		}
 		if len(typeName) != 0 {
 			enumMap := proto.EnumValueMap(typeName)
+			if len(enumMap) == 0 {
+				warn(b.Config, "protobuf enum is not registered, its definition has no values", "enum", typeName, "field", field.Name)
+			}
 			var enumItems EnumItems
 			for name, val := range enumMap {
 				enumItems = append(enumItems, EnumItem{value: val, name: name})
@@ -121,6 +173,54 @@ func setEnumValues(b definitionBuilder, prop *spec.Schema, field reflect.StructF
 	}
 }
 
+// setTypeEnumValues sets the enum of a property of a named primitive type, such as type Status string,
+// to its values, see enumValuesOf, unless its enum tag already has.
+func setTypeEnumValues(b definitionBuilder, prop *spec.Schema, t reflect.Type) {
+	if len(prop.Enum) == 0 {
+		prop.Enum = enumValuesOf(t, b.Config)
+	}
+}
+
+// enumValuesOf returns the values of a named primitive type from the EnumValueFunc or else
+// from its Values method, if it returns a slice of the type.
+func enumValuesOf(t reflect.Type, cfg Config) []interface{} {
+	if t.PkgPath() == "" {
+		return nil
+	}
+	if cfg.EnumValueFunc != nil {
+		if values := cfg.EnumValueFunc(t); len(values) > 0 {
+			return values
+		}
+	}
+	method, ok := t.MethodByName("Values")
+	if !ok || method.Type.NumIn() != 1 || method.Type.NumOut() != 1 || method.Type.Out(0) != reflect.SliceOf(t) {
+		return nil
+	}
+	list := method.Func.Call([]reflect.Value{reflect.Zero(t)})[0]
+	var values []interface{}
+	for i := 0; i < list.Len(); i++ {
+		values = append(values, primitiveValue(list.Index(i)))
+	}
+	return values
+}
+
+// primitiveValue returns the value of a named primitive type as its unnamed type, as it encodes in JSON.
+func primitiveValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return v.Interface()
+}
+
 func setFormat(prop *spec.Schema, field reflect.StructField) {
 	if tag := field.Tag.Get("format"); tag != "" {
 		prop.Format = tag
@@ -173,6 +273,129 @@ func setType(prop *spec.Schema, field reflect.StructField) {
 	}
 }
 
+// setPatternProperties sets the patternProperties of a map from its tag of pattern:type pairs separated by commas,
+// e.g. `patternProperties:"^x-:string"`. The pattern ends at the last colon of a pair, see typeNameSchema for the type.
+// The tag of a field that is not a map is ignored with a warning.
+func setPatternProperties(b definitionBuilder, prop *spec.Schema, field reflect.StructField) {
+	tag := field.Tag.Get("patternProperties")
+	if tag == "" {
+		return
+	}
+	if indirectKind(field.Type) != reflect.Map {
+		warn(b.Config, "patternProperties tag is ignored, the field is not a map", "field", field.Name, "type", field.Type.String())
+		return
+	}
+	for _, pair := range strings.Split(tag, ",") {
+		i := strings.LastIndex(pair, ":")
+		if i <= 0 || i == len(pair)-1 {
+			continue
+		}
+		if prop.PatternProperties == nil {
+			prop.PatternProperties = spec.SchemaProperties{}
+		}
+		prop.PatternProperties[pair[:i]] = typeNameSchema(strings.TrimSpace(pair[i+1:]))
+	}
+}
+
+// setPropertyNames sets the propertyNames keyword of JSON Schema draft-06 of a map from its tag,
+// the pattern that the keys must match. The spec package does not model the keyword.
+func setPropertyNames(prop *spec.Schema, field reflect.StructField) {
+	tag := field.Tag.Get("propertyNames")
+	if tag == "" || indirectKind(field.Type) != reflect.Map {
+		return
+	}
+	addExtraProp(prop, "propertyNames", &spec.Schema{SchemaProps: spec.SchemaProps{Pattern: tag}})
+}
+
+// setContains sets the contains keyword of JSON Schema draft-06 of an array from its tag, the type of which
+// at least one item must be, see typeNameSchema. A definition is named as in the document, e.g. `contains:"pets.Cat"`.
+// The spec package does not model the keyword.
+func setContains(prop *spec.Schema, field reflect.StructField) {
+	tag := field.Tag.Get("contains")
+	if kind := indirectKind(field.Type); tag == "" || kind != reflect.Slice && kind != reflect.Array {
+		return
+	}
+	contains := typeNameSchema(tag)
+	addExtraProp(prop, "contains", &contains)
+}
+
+// setUnevaluated sets the unevaluatedProperties keyword of an object or map, or the unevaluatedItems keyword of an array,
+// from the boolean tag of the same name, if Config.JSONSchemaDraft is 2019-09 or later. The spec package does not model them.
+func setUnevaluated(b definitionBuilder, prop *spec.Schema, field reflect.StructField) {
+	if !jsonSchemaDraftAtLeast(b.Config, JSONSchemaDraft201909) {
+		return
+	}
+	key := "unevaluatedProperties"
+	switch indirectKind(field.Type) {
+	case reflect.Slice, reflect.Array:
+		key = "unevaluatedItems"
+	case reflect.Struct, reflect.Map, reflect.Interface:
+	default:
+		return
+	}
+	if value, err := strconv.ParseBool(field.Tag.Get(key)); err == nil {
+		addExtraProp(prop, key, value)
+	}
+}
+
+// indirectKind returns the kind of the type or, for a pointer, of its element.
+func indirectKind(t reflect.Type) reflect.Kind {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem().Kind()
+	}
+	return t.Kind()
+}
+
+// typeNameSchema returns the schema of a type named in a tag: a JSON Schema type, a Go primitive type
+// or else the name of a definition.
+func typeNameSchema(name string) spec.Schema {
+	switch name {
+	case "string", "integer", "number", "boolean", "object", "array", "null":
+		return spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{name}}}
+	}
+	if mapped := jsonSchemaType(name); mapped != name {
+		return spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{mapped}}}
+	}
+	return spec.Schema{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef(definitionRoot + name)}}
+}
+
+// dropUnknownTypeNames removes the subschemas of the contains and patternProperties tags that refer to
+// a definition that was not built, as the tag names a definition that no field or route refers to by its Go type.
+func dropUnknownTypeNames(cfg Config, definitions spec.Definitions) {
+	unknown := func(sub *spec.Schema) (string, bool) {
+		ref := sub.Ref.String()
+		name := strings.TrimPrefix(ref, definitionRoot)
+		if name == ref {
+			return "", false
+		}
+		_, ok := definitions[name]
+		return name, !ok
+	}
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, defName := range names {
+		def := definitions[defName]
+		walkSchemas(&def, func(s *spec.Schema) {
+			if contains, ok := s.ExtraProps["contains"].(*spec.Schema); ok {
+				if name, ok := unknown(contains); ok {
+					warn(cfg, "contains tag names an unknown definition", "definition", defName, "type", name)
+					delete(s.ExtraProps, "contains")
+				}
+			}
+			for pattern, each := range s.PatternProperties {
+				if name, ok := unknown(&each); ok {
+					warn(cfg, "patternProperties tag names an unknown definition", "definition", defName, "type", name)
+					delete(s.PatternProperties, pattern)
+				}
+			}
+		})
+		definitions[defName] = def
+	}
+}
+
 func setUniqueItems(prop *spec.Schema, field reflect.StructField) {
 	tag := field.Tag.Get("unique")
 	switch tag {
@@ -193,17 +416,88 @@ func setReadOnly(prop *spec.Schema, field reflect.StructField) {
 	}
 }
 
+// setXMLMetadata sets the XML object of the property from the xml tag of the field: its element or attribute name,
+// with namespace if any, attr for an attribute and a>b for an array wrapped in element a of elements b.
+// The character data of an element has no XML object, its property gets the x-xml-chardata extension.
+func setXMLMetadata(prop *spec.Schema, field reflect.StructField) {
+	tag, ok := field.Tag.Lookup("xml")
+	if !ok || tag == "-" {
+		return
+	}
+	parts := strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		switch option {
+		case "attr":
+			prop.XML = xmlObject(prop.XML)
+			prop.XML.Attribute = true
+		case "chardata":
+			initPropExtensions(&prop.Extensions)
+			prop.Extensions["x-xml-chardata"] = true
+			return
+		case "innerxml", "comment", "any":
+			return
+		}
+	}
+	name := parts[0]
+	if name == "" {
+		return
+	}
+	if path := strings.Split(name, ">"); len(path) > 1 {
+		name = path[len(path)-1]
+		if kind := field.Type.Kind(); kind == reflect.Slice || kind == reflect.Array {
+			prop.XML = xmlObject(prop.XML)
+			prop.XML.Name = path[len(path)-2]
+			prop.XML.Wrapped = true
+			return
+		}
+	}
+	prop.XML = xmlObject(prop.XML)
+	prop.XML.Namespace, prop.XML.Name = xmlNamespaceAndName(name)
+}
+
+// setXMLItemsName sets the XML name of the items of an array wrapped by the a>b path of the xml tag of its field to b.
+func setXMLItemsName(items *spec.Schema, field reflect.StructField) {
+	name := strings.Split(field.Tag.Get("xml"), ",")[0]
+	if path := strings.Split(name, ">"); len(path) > 1 && items.Ref.String() == "" {
+		items.XML = xmlObject(items.XML)
+		items.XML.Name = path[len(path)-1]
+	}
+}
+
+func xmlObject(x *spec.XMLObject) *spec.XMLObject {
+	if x == nil {
+		return new(spec.XMLObject)
+	}
+	return x
+}
+
+// xmlNamespaceAndName splits the name of an xml tag, which is prefixed by its namespace and a space if any.
+func xmlNamespaceAndName(name string) (namespace, local string) {
+	if i := strings.LastIndex(name, " "); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
 func setPropertyMetadata(b definitionBuilder, prop *spec.Schema, field reflect.StructField) {
 	setDescription(prop, field)
+	setFieldDescription(b, prop, field)
 	setDefaultValue(prop, field)
 	setEnumValues(b, prop, field)
+	setConstValue(prop, field)
 	setFormat(prop, field)
 	setMinimum(prop, field)
 	setMaximum(prop, field)
 	setPattern(prop, field)
+	setPatternProperties(b, prop, field)
+	setPropertyNames(prop, field)
+	setContains(prop, field)
+	setUnevaluated(b, prop, field)
 	setUniqueItems(prop, field)
 	setType(prop, field)
 	setReadOnly(prop, field)
 	setIsNullableValue(prop, field)
 	setGoNameValue(prop, field)
+	setKubernetesExtensions(b, prop, field)
+	setXMLMetadata(prop, field)
 }
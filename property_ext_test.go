@@ -1,10 +1,15 @@
 package restfulspec
 
 import (
+	"encoding/xml"
 	"fmt"
 	"net"
+	"reflect"
 	"strings"
 	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/go-openapi/spec"
 )
 
 // nolint:paralleltest
@@ -26,6 +31,8 @@ func TestThatExtraTagsAreReadIntoModel(t *testing.T) {
 		NotNullableField string `x-nullable:"false"`
 		UUID             string `type:"string" format:"UUID"`
 		XGoName          string `x-go-name:"specgoname"`
+		Kind             string `const:"dog"`
+		Version          int    `const:"2"`
 	}
 	d := definitionsFromStruct(Anything{})
 	props, _ := d["restfulspec.Anything"]
@@ -106,4 +113,327 @@ func TestThatExtraTagsAreReadIntoModel(t *testing.T) {
 	if got, want := p13.Extensions["x-go-name"], "specgoname"; got != want {
 		t.Errorf("got %v want %v", got, want)
 	}
+	p14, _ := props.Properties["Kind"]
+	if got, want := fmt.Sprintf("%#v", p14.Enum), `[]interface {}{"dog"}`; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := p14.Extensions["x-const"], true; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	p15, _ := props.Properties["Version"]
+	if got, want := fmt.Sprintf("%#v", p15.Enum), `[]interface {}{2}`; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+type Color string
+
+func (Color) Values() []Color { return []Color{"red", "green"} }
+
+type Size int
+
+type Shirt struct {
+	Color   Color
+	Colors  []Color
+	Trim    *Color
+	Size    Size
+	Primary Color `enum:"red"`
+}
+
+func TestEnumValuesOfNamedTypes(t *testing.T) {
+	cfg := Config{EnumValueFunc: func(t reflect.Type) []interface{} {
+		if t == reflect.TypeOf(Size(0)) {
+			return []interface{}{1, 2, 3}
+		}
+		return nil
+	}}
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: cfg}
+	db.addModelFrom(Shirt{})
+	props := db.Definitions["restfulspec.Shirt"].Properties
+	for _, each := range []spec.Schema{props["Color"], *props["Colors"].Items.Schema, props["Trim"]} {
+		if got, want := fmt.Sprintf("%#v", each.Enum), `[]interface {}{"red", "green"}`; got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+	}
+	if got, want := fmt.Sprintf("%v", props["Size"].Enum), "[1 2 3]"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := fmt.Sprintf("%v", props["Primary"].Enum), "[red]"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	body, _ := namedPrimitiveSchema(reflect.TypeOf(Color("")), cfg)
+	if got, want := len(body.Enum), 2; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got := enumValuesOf(reflect.TypeOf(""), cfg); got != nil {
+		t.Errorf("got %v want nil", got)
+	}
+}
+
+type Container struct {
+	Name string
+}
+
+type PodSpec struct {
+	Containers []Container    `k8s_list_type:"map" k8s_list_map_keys:"name, image"`
+	Finalizers []string       `k8s_list_type:"set"`
+	Config     map[string]any `k8s_preserve_unknown_fields:"true"`
+}
+
+func TestKubernetesExtensionMode(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(PodSpec{})
+	if ext := db.Definitions["restfulspec.PodSpec"].Properties["Containers"].Extensions; ext != nil {
+		t.Errorf("got %v want none", ext)
+	}
+
+	db = definitionBuilder{Definitions: spec.Definitions{}, Config: Config{KubernetesExtensionMode: true}}
+	db.addModelFrom(PodSpec{})
+	props := db.Definitions["restfulspec.PodSpec"].Properties
+	containers := props["Containers"].Extensions
+	if got, want := containers["x-kubernetes-list-type"], "map"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := fmt.Sprint(containers["x-kubernetes-list-map-keys"]), "[name image]"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := props["Finalizers"].Extensions["x-kubernetes-list-type"], "set"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := props["Config"].Extensions["x-kubernetes-preserve-unknown-fields"], true; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+type Book struct {
+	XMLName xml.Name `xml:"urn:books book"`
+	ISBN    string   `json:"isbn" xml:"isbn,attr"`
+	Title   string   `json:"title" xml:"urn:books title"`
+	Authors []string `json:"authors" xml:"authors>author"`
+	Notes   string   `json:"notes" xml:",chardata"`
+	Draft   string   `json:"draft" xml:"-"`
+}
+
+func TestXMLMetadata(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Book{})
+	book := db.Definitions["restfulspec.Book"]
+	if got, want := *book.XML, (spec.XMLObject{Name: "book", Namespace: "urn:books"}); got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	props := book.Properties
+	if got, want := *props["isbn"].XML, (spec.XMLObject{Name: "isbn", Attribute: true}); got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := *props["title"].XML, (spec.XMLObject{Name: "title", Namespace: "urn:books"}); got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	authors := props["authors"]
+	if got, want := *authors.XML, (spec.XMLObject{Name: "authors", Wrapped: true}); got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := authors.Items.Schema.XML.Name, "author"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := props["notes"].Extensions["x-xml-chardata"], true; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if props["notes"].XML != nil || props["draft"].XML != nil {
+		t.Errorf("got %v and %v want none", props["notes"].XML, props["draft"].XML)
+	}
+}
+
+type Contact struct {
+	Email string `nullable:"true"`
+	Phone string `nullable:"false"`
+	Fax   string `x-nullable:"true" nullable:"false"`
+}
+
+func TestNullableTag(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Contact{})
+	props := db.Definitions["restfulspec.Contact"].Properties
+	for name, want := range map[string]bool{"Email": true, "Phone": false, "Fax": true} {
+		if got := props[name].Extensions["x-nullable"]; got != want {
+			t.Errorf("%s: got %v want %v", name, got, want)
+		}
+	}
+
+	db = definitionBuilder{Definitions: spec.Definitions{}, Config: Config{NullableStrategy: NullableKeyword, OpenAPIVersion: OpenAPIVersion3}}
+	db.addModelFrom(Contact{})
+	props = db.Definitions["restfulspec.Contact"].Properties
+	for name, want := range map[string]bool{"Email": true, "Phone": false, "Fax": true} {
+		if got := props[name].Nullable; got != want || props[name].Extensions != nil {
+			t.Errorf("%s: got %s want nullable %v", name, asJSON(props[name]), want)
+		}
+	}
+}
+
+type Annotated struct {
+	Annotations map[string]interface{} `patternProperties:"^x-:string,^count-[0-9]{1}:int64,^owner-:Owner,bad"`
+}
+
+func TestPatternProperties(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Annotated{})
+	patterns := db.Definitions["restfulspec.Annotated"].Properties["Annotations"].PatternProperties
+	if got, want := len(patterns), 3; got != want {
+		t.Fatalf("got %v want %v", asJSON(patterns), want)
+	}
+	if got, want := patterns["^x-"].Type[0], "string"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := patterns["^count-[0-9]{1}"].Type[0], "integer"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	ref := patterns["^owner-"].Ref
+	if got, want := ref.String(), "#/definitions/Owner"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+type MisAnnotated struct {
+	Name string `patternProperties:"^x-:string"`
+}
+
+func TestPatternPropertiesOfNonMap(t *testing.T) {
+	logger := new(recordingWarnLogger)
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{Logger: logger}}
+	db.addModelFrom(MisAnnotated{})
+	if got := db.Definitions["restfulspec.MisAnnotated"].Properties["Name"].PatternProperties; got != nil {
+		t.Errorf("got %v want no patternProperties", asJSON(got))
+	}
+	if got, want := len(logger.warnings), 1; got != want {
+		t.Fatalf("got %d warnings want %d: %v", got, want, logger.warnings)
+	}
+	if !strings.Contains(logger.warnings[0], `field="Name" type="string"`) {
+		t.Errorf("got %v", logger.warnings[0])
+	}
+}
+
+type Tagged struct {
+	Labels map[string]string `propertyNames:"^[a-z][a-z0-9-]*$"`
+	Name   string            `propertyNames:"^ignored$"`
+}
+
+func TestPropertyNames(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Tagged{})
+	props := db.Definitions["restfulspec.Tagged"].Properties
+	labels := props["Labels"]
+	names, ok := labels.ExtraProps["propertyNames"].(*spec.Schema)
+	if !ok {
+		t.Fatalf("got %v", asJSON(labels))
+	}
+	if got, want := names.Pattern, "^[a-z][a-z0-9-]*$"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if extra := props["Name"].ExtraProps; extra != nil {
+		t.Errorf("got %v want none", extra)
+	}
+}
+
+type Shelter struct {
+	Animals []interface{} `contains:"restfulspec.Pet"`
+	Names   []string      `contains:"string"`
+	Address string        `contains:"restfulspec.Pet"`
+}
+
+func TestContains(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{}}
+	db.addModelFrom(Shelter{})
+	props := db.Definitions["restfulspec.Shelter"].Properties
+	animals, ok := props["Animals"].ExtraProps["contains"].(*spec.Schema)
+	if !ok {
+		t.Fatalf("got %v", asJSON(props["Animals"]))
+	}
+	if ref := animals.Ref; ref.String() != "#/definitions/restfulspec.Pet" {
+		t.Errorf("got %v", ref.String())
+	}
+	names, ok := props["Names"].ExtraProps["contains"].(*spec.Schema)
+	if !ok || names.Type[0] != "string" {
+		t.Errorf("got %v", asJSON(props["Names"]))
+	}
+	if extra := props["Address"].ExtraProps; extra != nil {
+		t.Errorf("got %v want none", extra)
+	}
+}
+
+type Aviary struct {
+	Animals []interface{}          `contains:"restfulspec.Pet"`
+	Strays  []interface{}          `contains:"restfulspec.Stray"`
+	Tags    map[string]interface{} `patternProperties:"^pet-:restfulspec.Pet,^stray-:restfulspec.Stray"`
+}
+
+func TestTagsNamingUnknownDefinitions(t *testing.T) {
+	logger := new(recordingWarnLogger)
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/aviaries").To(dummy).Returns(200, "ok", Aviary{}).Metadata(KeyOpenAPITags, []string{"aviaries"}))
+	ws.Route(ws.GET("/pets").To(dummy).Returns(200, "ok", Pet{}).Metadata(KeyOpenAPITags, []string{"pets"}))
+	swagger := BuildSwagger(Config{WebServices: []*restful.WebService{ws}, Logger: logger})
+	aviary := swagger.Definitions["restfulspec.Aviary"]
+	if _, ok := aviary.Properties["Animals"].ExtraProps["contains"]; !ok {
+		t.Errorf("got %v want contains of restfulspec.Pet", asJSON(aviary.Properties["Animals"]))
+	}
+	if extra := aviary.Properties["Strays"].ExtraProps; extra["contains"] != nil {
+		t.Errorf("got %v want no contains", extra)
+	}
+	patterns := aviary.Properties["Tags"].PatternProperties
+	if _, ok := patterns["^stray-"]; ok || len(patterns) != 1 {
+		t.Errorf("got %v want only ^pet-", asJSON(patterns))
+	}
+	if got, want := len(logger.warnings), 2; got != want {
+		t.Errorf("got %v want %d warnings", logger.warnings, want)
+	}
+
+	filtered := FilterSwagger(swagger, []string{"aviaries"})
+	if _, ok := filtered.Definitions["restfulspec.Pet"]; !ok {
+		t.Errorf("got %v want restfulspec.Pet that contains refers to", asJSON(filtered.Definitions))
+	}
+	resolved, err := ResolveRefs(filtered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	animals := resolved.Paths.Paths["/aviaries"].Get.Responses.StatusCodeResponses[200].Schema.Properties["Animals"]
+	if contains := extraPropSchema(&animals, "contains"); contains == nil || contains.Properties["kind"].Enum == nil {
+		t.Errorf("got %v want inlined restfulspec.Pet", asJSON(animals))
+	}
+}
+
+type Bundle struct {
+	Settings map[string]string `unevaluatedProperties:"false"`
+	Parts    []string          `unevaluatedItems:"false"`
+	Owner    Container         `unevaluatedProperties:"true"`
+	Name     string            `unevaluatedProperties:"false"`
+}
+
+func TestUnevaluatedKeywords(t *testing.T) {
+	db := definitionBuilder{Definitions: spec.Definitions{}, Config: Config{JSONSchemaDraft: JSONSchemaDraft07}}
+	db.addModelFrom(Bundle{})
+	for name, prop := range db.Definitions["restfulspec.Bundle"].Properties {
+		if prop.ExtraProps != nil {
+			t.Errorf("draft-07 %s: got %v want none", name, prop.ExtraProps)
+		}
+	}
+
+	for _, config := range []Config{{JSONSchemaDraft: JSONSchemaDraft201909}, {OpenAPIVersion: OpenAPIVersion31}} {
+		db = definitionBuilder{Definitions: spec.Definitions{}, Config: config}
+		db.addModelFrom(Bundle{})
+		props := db.Definitions["restfulspec.Bundle"].Properties
+		if got, want := props["Settings"].ExtraProps["unevaluatedProperties"], false; got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+		if got, want := props["Parts"].ExtraProps["unevaluatedItems"], false; got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+		if got, want := props["Owner"].ExtraProps["unevaluatedProperties"], true; got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+		if extra := props["Name"].ExtraProps; extra != nil {
+			t.Errorf("got %v want none", extra)
+		}
+	}
+	if err := validateConfig(Config{JSONSchemaDraft: "draft-08"}); err == nil {
+		t.Error("expected error for unknown JSON Schema draft")
+	}
 }
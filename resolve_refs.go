@@ -0,0 +1,184 @@
+package restfulspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// DefaultResolveRefsDepth is the number of times ResolveRefs inlines a circular reference.
+const DefaultResolveRefsDepth = 3
+
+// ResolveRefs returns a copy of the swagger in which each $ref to a definition is replaced by a copy of that definition.
+// A definition that refers to itself, directly or indirectly, is inlined DefaultResolveRefsDepth times
+// after which the $ref is left as is. Only the definitions that are still referenced are kept.
+// It returns an error if a $ref refers to a definition that does not exist.
+func ResolveRefs(swagger *spec.Swagger) (*spec.Swagger, error) {
+	return ResolveRefsWithConfig(swagger, Config{})
+}
+
+// ResolveRefsWithConfig is ResolveRefs in which circular references are inlined ResolveRefsDepth times of the config.
+func ResolveRefsWithConfig(swagger *spec.Swagger, config Config) (*spec.Swagger, error) {
+	resolved, err := cloneSwagger(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("copy swagger: %v", err)
+	}
+	r := refResolver{definitions: swagger.Definitions, depth: config.ResolveRefsDepth, visiting: map[string]int{}}
+	if r.depth <= 0 {
+		r.depth = DefaultResolveRefsDepth
+	}
+	for name, def := range resolved.Definitions {
+		r.visiting[name]++
+		err := r.inline(&def)
+		r.visiting[name]--
+		if err != nil {
+			return nil, err
+		}
+		resolved.Definitions[name] = def
+	}
+	for path, item := range pathsOf(resolved) {
+		if err := r.inlineParameters(item.Parameters); err != nil {
+			return nil, err
+		}
+		for _, op := range pathItemOperations(&item) {
+			if err := r.inlineParameters(op.Parameters); err != nil {
+				return nil, err
+			}
+			if op.Responses == nil {
+				continue
+			}
+			if err := r.inlineResponse(op.Responses.Default); err != nil {
+				return nil, err
+			}
+			for code, each := range op.Responses.StatusCodeResponses {
+				if err := r.inlineResponse(&each); err != nil {
+					return nil, err
+				}
+				op.Responses.StatusCodeResponses[code] = each
+			}
+		}
+		resolved.Paths.Paths[path] = item
+	}
+	for name, each := range resolved.Parameters {
+		if err := r.inline(each.Schema); err != nil {
+			return nil, err
+		}
+		resolved.Parameters[name] = each
+	}
+	for name, each := range resolved.Responses {
+		if err := r.inlineResponse(&each); err != nil {
+			return nil, err
+		}
+		resolved.Responses[name] = each
+	}
+	resolved.Definitions = referencedDefinitions(resolved)
+	return resolved, nil
+}
+
+type refResolver struct {
+	definitions spec.Definitions
+	depth       int
+	// visiting counts the definitions that are being inlined
+	visiting map[string]int
+}
+
+func (r refResolver) inlineParameters(params []spec.Parameter) error {
+	for i := range params {
+		if err := r.inline(params[i].Schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r refResolver) inlineResponse(resp *spec.Response) error {
+	if resp == nil {
+		return nil
+	}
+	return r.inline(resp.Schema)
+}
+
+// inline replaces the references in the schema and its subschemas by the definitions they refer to.
+func (r refResolver) inline(s *spec.Schema) error {
+	if s == nil {
+		return nil
+	}
+	if ref := s.Ref.String(); strings.HasPrefix(ref, definitionRoot) {
+		name := strings.TrimPrefix(ref, definitionRoot)
+		def, ok := r.definitions[name]
+		if !ok {
+			return fmt.Errorf("definition %q of %s not found", name, ref)
+		}
+		if r.visiting[name] >= r.depth {
+			return nil
+		}
+		copied, err := cloneSchema(def)
+		if err != nil {
+			return fmt.Errorf("copy definition %q: %v", name, err)
+		}
+		r.visiting[name]++
+		err = r.inline(&copied)
+		r.visiting[name]--
+		*s = copied
+		return err
+	}
+	for name, each := range s.Properties {
+		if err := r.inline(&each); err != nil {
+			return err
+		}
+		s.Properties[name] = each
+	}
+	for name, each := range s.PatternProperties {
+		if err := r.inline(&each); err != nil {
+			return err
+		}
+		s.PatternProperties[name] = each
+	}
+	if s.Items != nil {
+		if err := r.inline(s.Items.Schema); err != nil {
+			return err
+		}
+		for i := range s.Items.Schemas {
+			if err := r.inline(&s.Items.Schemas[i]); err != nil {
+				return err
+			}
+		}
+	}
+	if s.AdditionalProperties != nil {
+		if err := r.inline(s.AdditionalProperties.Schema); err != nil {
+			return err
+		}
+	}
+	for _, list := range [][]spec.Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for i := range list {
+			if err := r.inline(&list[i]); err != nil {
+				return err
+			}
+		}
+	}
+	if err := r.inline(s.Not); err != nil {
+		return err
+	}
+	for _, key := range subschemaKeywords {
+		if sub := extraPropSchema(s, key); sub != nil {
+			if err := r.inline(sub); err != nil {
+				return err
+			}
+			s.ExtraProps[key] = sub
+		}
+	}
+	return nil
+}
+
+// cloneSchema returns a deep copy of the schema.
+func cloneSchema(s spec.Schema) (spec.Schema, error) {
+	var clone spec.Schema
+	data, err := json.Marshal(s)
+	if err != nil {
+		return clone, err
+	}
+	err = json.Unmarshal(data, &clone)
+	return clone, err
+}
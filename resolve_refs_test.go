@@ -0,0 +1,72 @@
+package restfulspec
+
+import (
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/go-openapi/spec"
+)
+
+type Category struct {
+	Name          string
+	Subcategories []Category
+}
+
+func TestResolveRefs(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Route(ws.POST("/kennels").To(dummy).Reads(Kennel{}).Returns(200, "ok", Category{}))
+	swagger := BuildSwagger(Config{WebServices: []*restful.WebService{ws}})
+
+	resolved, err := ResolveRefs(swagger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := resolved.Paths.Paths["/kennels"].Post
+	body := op.Parameters[0].Schema
+	if body.Ref.String() != "" {
+		t.Errorf("got ref %s", body.Ref.String())
+	}
+	if got, want := body.Properties["Keeper"].Properties["Name"].Type[0], "string"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	// the category is inlined three times, the fourth level refers to the definition
+	s := op.Responses.StatusCodeResponses[200].Schema
+	for i := 0; i < DefaultResolveRefsDepth; i++ {
+		if s.Ref.String() != "" {
+			t.Fatalf("level %d: got ref %s", i, s.Ref.String())
+		}
+		s = s.Properties["Subcategories"].Items.Schema
+	}
+	if got, want := s.Ref.String(), "#/definitions/restfulspec.Category"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := len(resolved.Definitions), 1; got != want {
+		t.Errorf("got %d definitions want %d", got, want)
+	}
+
+	// the original is not modified
+	if got := swagger.Paths.Paths["/kennels"].Post.Parameters[0].Schema.Ref.String(); got == "" {
+		t.Error("original should keep its ref")
+	}
+}
+
+func TestResolveRefsWithDepth(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/categories").To(dummy).Returns(200, "ok", Category{}))
+	swagger := BuildSwagger(Config{WebServices: []*restful.WebService{ws}})
+
+	resolved, err := ResolveRefsWithConfig(swagger, Config{ResolveRefsDepth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := resolved.Paths.Paths["/categories"].Get.Responses.StatusCodeResponses[200].Schema
+	if got, want := s.Properties["Subcategories"].Items.Schema.Ref.String(), "#/definitions/restfulspec.Category"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	swagger.Definitions = spec.Definitions{}
+	if _, err := ResolveRefs(swagger); err == nil {
+		t.Error("expected error for missing definition")
+	}
+}
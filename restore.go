@@ -0,0 +1,139 @@
+package restfulspec
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/go-openapi/spec"
+)
+
+// RestoreFromSpec adds a WebService to the container with a route for each operation of the Swagger object,
+// on its basePath, such as for contract tests or an API gateway without the implementation of the operations.
+// The routes have the method, path, parameters, content types, documentation and response codes of the operations;
+// their functions respond with 501 Not Implemented.
+func RestoreFromSpec(swagger *spec.Swagger, container *restful.Container) {
+	ws := new(restful.WebService)
+	if swagger.BasePath != "" && swagger.BasePath != "/" {
+		ws.Path(swagger.BasePath)
+	}
+	ws.Consumes(swagger.Consumes...)
+	ws.Produces(swagger.Produces...)
+	paths := pathsOf(swagger)
+	keys := make([]string, 0, len(paths))
+	for path := range paths {
+		keys = append(keys, path)
+	}
+	sort.Strings(keys)
+	for _, path := range keys {
+		item := paths[path]
+		byMethod := pathItemOperationsByMethod(item)
+		methods := make([]string, 0, len(byMethod))
+		for method := range byMethod {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			ws.Route(restoredRoute(swagger, ws, method, path, item, byMethod[method]))
+		}
+	}
+	container.Add(ws)
+}
+
+func restoredRoute(swagger *spec.Swagger, ws *restful.WebService, method, path string, item spec.PathItem, op *spec.Operation) *restful.RouteBuilder {
+	rb := ws.Method(method).Path(path).To(notImplemented).
+		Operation(op.ID).
+		Doc(op.Summary).
+		Notes(op.Description)
+	if len(op.Consumes) > 0 {
+		rb.Consumes(op.Consumes...)
+	}
+	if len(op.Produces) > 0 {
+		rb.Produces(op.Produces...)
+	}
+	if len(op.Tags) > 0 {
+		rb.Metadata(KeyOpenAPITags, op.Tags)
+	}
+	if op.Deprecated {
+		rb.Deprecate()
+	}
+	opParams := resolvedParameters(swagger, op.Parameters)
+	for _, each := range resolvedParameters(swagger, item.Parameters) {
+		if !hasParameter(opParams, each) {
+			rb.Param(restoredParameter(ws, each))
+		}
+	}
+	for _, each := range opParams {
+		rb.Param(restoredParameter(ws, each))
+	}
+	if op.Responses != nil {
+		codes := make([]int, 0, len(op.Responses.StatusCodeResponses))
+		for code := range op.Responses.StatusCodeResponses {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			rb.Returns(code, op.Responses.StatusCodeResponses[code].Description, nil)
+		}
+	}
+	return rb
+}
+
+// resolvedParameters returns the parameters with those that refer to a global parameter of the swagger replaced by it.
+// Unknown references are left out.
+func resolvedParameters(swagger *spec.Swagger, params []spec.Parameter) []spec.Parameter {
+	resolved := make([]spec.Parameter, 0, len(params))
+	for _, each := range params {
+		if ref := each.Ref; ref.String() != "" {
+			global, ok := swagger.Parameters[strings.TrimPrefix(ref.String(), "#/parameters/")]
+			if !ok {
+				continue
+			}
+			each = global
+		}
+		resolved = append(resolved, each)
+	}
+	return resolved
+}
+
+// hasParameter returns true if the parameters have one with the name and location of the parameter.
+// Those of an operation take precedence over those of its path item.
+func hasParameter(params []spec.Parameter, p spec.Parameter) bool {
+	for _, each := range params {
+		if each.Name == p.Name && each.In == p.In {
+			return true
+		}
+	}
+	return false
+}
+
+// restoredParameter returns the restful parameter of the spec parameter.
+func restoredParameter(ws *restful.WebService, p spec.Parameter) *restful.Parameter {
+	var param *restful.Parameter
+	switch p.In {
+	case "path":
+		param = ws.PathParameter(p.Name, p.Description)
+	case "query":
+		param = ws.QueryParameter(p.Name, p.Description)
+	case "header":
+		param = ws.HeaderParameter(p.Name, p.Description)
+	case "formData":
+		param = ws.FormParameter(p.Name, p.Description)
+	default:
+		param = ws.BodyParameter(p.Name, p.Description)
+	}
+	param.Required(p.Required)
+	if p.Type != "" {
+		param.DataType(p.Type)
+	}
+	if p.Format != "" {
+		param.DataFormat(p.Format)
+	}
+	return param
+}
+
+// notImplemented is the function of the routes of RestoreFromSpec.
+func notImplemented(req *restful.Request, resp *restful.Response) {
+	resp.WriteErrorString(http.StatusNotImplemented, http.StatusText(http.StatusNotImplemented))
+}
@@ -0,0 +1,64 @@
+package restfulspec
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/go-openapi/spec"
+)
+
+func TestRestoreFromSpec(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/samples").Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("/{id}").To(dummy).
+		Doc("get a sample").
+		Operation("getSample").
+		Metadata(KeyOpenAPITags, []string{"samples"}).
+		Param(ws.PathParameter("id", "identifier")).
+		Param(ws.QueryParameter("verbose", "more fields").DataType("boolean")).
+		Returns(200, "OK", Sample{}).
+		Returns(404, "not found", nil))
+	ws.Route(ws.DELETE("/{id}").To(dummy).Param(ws.PathParameter("id", "identifier")))
+	original := BuildSwagger(Config{WebServices: []*restful.WebService{ws}})
+	original.Parameters = map[string]spec.Parameter{"trace": *spec.HeaderParam("X-Trace").Typed("string", "")}
+	get := original.Paths.Paths["/samples/{id}"].Get
+	get.Parameters = append(get.Parameters, *spec.ParamRef("#/parameters/trace"))
+
+	container := restful.NewContainer()
+	RestoreFromSpec(original, container)
+
+	rec := httptest.NewRecorder()
+	container.Dispatch(rec, httptest.NewRequest(http.MethodGet, "/samples/42", nil))
+	if got, want := rec.Code, http.StatusNotImplemented; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	restored := BuildSwagger(Config{WebServices: container.RegisteredWebServices()})
+	item := restored.Paths.Paths["/samples/{id}"]
+	if item.Get == nil || item.Delete == nil {
+		t.Fatalf("got %v", asJSON(item))
+	}
+	if got, want := item.Get.ID, "getSample"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := item.Get.Summary, "get a sample"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := len(item.Get.Tags), 1; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	params := map[string]string{}
+	for _, each := range append(item.Parameters, item.Get.Parameters...) {
+		params[each.In+":"+each.Name] = each.Type
+	}
+	for key, want := range map[string]string{"path:id": "string", "query:verbose": "boolean", "header:X-Trace": "string"} {
+		if got := params[key]; got != want {
+			t.Errorf("%s: got %q want %q", key, got, want)
+		}
+	}
+	if _, ok := item.Get.Responses.StatusCodeResponses[404]; !ok {
+		t.Errorf("missing 404 in %v", asJSON(item.Get.Responses))
+	}
+}
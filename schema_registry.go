@@ -0,0 +1,141 @@
+package restfulspec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// SchemaRegistry tracks structurally identical inline schemas of definitions by content hash,
+// so that repeated ones can be replaced by a reference to a single shared definition.
+// Inline schemas are enums and objects with properties; annotations such as the description
+// of a field are not part of the structure and stay with the field.
+type SchemaRegistry struct {
+	entries map[string]*registryEntry
+	// hashes in order of appearance
+	hashes []string
+}
+
+type registryEntry struct {
+	name   string
+	schema spec.Schema
+	count  int
+}
+
+// NewSchemaRegistry returns an empty SchemaRegistry.
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{entries: map[string]*registryEntry{}}
+}
+
+// Deduplicate replaces each inline schema that occurs more than once in the definitions by a reference
+// to a new definition, named after the first property it occurs in.
+func (r *SchemaRegistry) Deduplicate(definitions spec.Definitions) {
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := definitions[name]
+		r.visit(&def, "", func(prop string, s *spec.Schema) {
+			r.register(prop, s)
+		})
+	}
+	shared := map[string]string{}
+	for _, hash := range r.hashes {
+		entry := r.entries[hash]
+		if entry.count < 2 {
+			continue
+		}
+		entry.name = uniqueDefinitionName(definitions, entry.name)
+		definitions[entry.name] = entry.schema
+		shared[hash] = entry.name
+	}
+	if len(shared) == 0 {
+		return
+	}
+	for _, name := range names {
+		def := definitions[name]
+		r.visit(&def, "", func(prop string, s *spec.Schema) {
+			if sharedName, ok := shared[schemaHash(*s)]; ok {
+				*s = spec.Schema{
+					SchemaProps:      spec.SchemaProps{Ref: spec.MustCreateRef(definitionRoot + sharedName), Description: s.Description},
+					VendorExtensible: s.VendorExtensible,
+				}
+			}
+		})
+		definitions[name] = def
+	}
+}
+
+func (r *SchemaRegistry) register(prop string, s *spec.Schema) {
+	hash := schemaHash(*s)
+	entry, ok := r.entries[hash]
+	if !ok {
+		entry = &registryEntry{name: strings.ToUpper(prop[:1]) + prop[1:], schema: structuralSchema(*s)}
+		r.entries[hash] = entry
+		r.hashes = append(r.hashes, hash)
+	}
+	entry.count++
+}
+
+// visit calls the function with each inline schema in the properties and items of the schema, in name order.
+// It does not descend into inline schemas.
+func (r *SchemaRegistry) visit(s *spec.Schema, prop string, fn func(prop string, s *spec.Schema)) {
+	if prop != "" && isInlineSchema(s) {
+		fn(prop, s)
+		return
+	}
+	if s.Items != nil && s.Items.Schema != nil {
+		r.visit(s.Items.Schema, prop, fn)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		r.visit(s.AdditionalProperties.Schema, prop, fn)
+	}
+	props := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		props = append(props, name)
+	}
+	sort.Strings(props)
+	for _, name := range props {
+		each := s.Properties[name]
+		r.visit(&each, name, fn)
+		s.Properties[name] = each
+	}
+}
+
+func isInlineSchema(s *spec.Schema) bool {
+	return s.Ref.String() == "" && (len(s.Enum) > 0 || len(s.Properties) > 0)
+}
+
+// structuralSchema returns the schema without the annotations of the field it is used for.
+func structuralSchema(s spec.Schema) spec.Schema {
+	s.Description = ""
+	s.Title = ""
+	s.Example = nil
+	s.Extensions = nil
+	return s
+}
+
+func schemaHash(s spec.Schema) string {
+	data, _ := json.Marshal(structuralSchema(s))
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// uniqueDefinitionName returns the name, with a number appended if a definition by that name exists.
+func uniqueDefinitionName(definitions spec.Definitions, name string) string {
+	if _, ok := definitions[name]; !ok {
+		return name
+	}
+	for i := 2; ; i++ {
+		if _, ok := definitions[name+strconv.Itoa(i)]; !ok {
+			return name + strconv.Itoa(i)
+		}
+	}
+}
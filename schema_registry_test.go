@@ -0,0 +1,65 @@
+package restfulspec
+
+import (
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/go-openapi/spec"
+)
+
+type Order struct {
+	Status   string   `json:"status" enum:"open|closed" description:"status of the order"`
+	Payment  string   `json:"payment" enum:"cash|card"`
+	Statuses []string `json:"statuses"`
+}
+
+func (Order) PostBuildSwaggerSchemaHandler(sm *spec.Schema) {
+	prop := sm.Properties["statuses"]
+	prop.Items.Schema.Enum = []interface{}{"open", "closed"}
+	sm.Properties["statuses"] = prop
+}
+
+type Invoice struct {
+	Status string `json:"status" enum:"open|closed" description:"status of the invoice"`
+}
+
+func TestSchemaDeduplication(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/orders").To(dummy).Returns(200, "ok", Order{}))
+	ws.Route(ws.GET("/invoices").To(dummy).Returns(200, "ok", Invoice{}))
+
+	s := BuildSwagger(Config{WebServices: []*restful.WebService{ws}, EnableSchemaDeduplication: true})
+	t.Log(asJSON(s.Definitions))
+
+	status, ok := s.Definitions["Status"]
+	if !ok || len(status.Enum) != 2 || status.Description != "" {
+		t.Fatalf("got shared definition %#v", status)
+	}
+	order, invoice := s.Definitions["restfulspec.Order"], s.Definitions["restfulspec.Invoice"]
+	for _, each := range []spec.Schema{order.Properties["status"], invoice.Properties["status"], *order.Properties["statuses"].Items.Schema} {
+		if got, want := each.Ref.String(), "#/definitions/Status"; got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+	}
+	if got, want := invoice.Properties["status"].Description, "status of the invoice"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got := order.Properties["payment"]; got.Ref.String() != "" || len(got.Enum) != 2 {
+		t.Errorf("unique schema was replaced: %#v", got)
+	}
+
+	s = BuildSwagger(Config{WebServices: []*restful.WebService{ws}})
+	if _, ok := s.Definitions["Status"]; ok {
+		t.Errorf("deduplicated without EnableSchemaDeduplication")
+	}
+}
+
+func TestUniqueDefinitionName(t *testing.T) {
+	definitions := spec.Definitions{"Status": {}, "Status2": {}}
+	if got, want := uniqueDefinitionName(definitions, "Status"), "Status3"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := uniqueDefinitionName(definitions, "Kind"), "Kind"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
@@ -1,40 +1,307 @@
 package restfulspec
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
 	restful "github.com/emicklei/go-restful/v3"
 	"github.com/go-openapi/spec"
 )
 
+// MIME_YAML is the content type of the spec if Config.ServeAsYAML is set.
+const MIME_YAML = "application/yaml"
+
 // NewOpenAPIService returns a new WebService that provides the API documentation of all services
 // conform the OpenAPI documentation specifcation.
 func NewOpenAPIService(config Config) *restful.WebService {
 
 	ws := new(restful.WebService)
 	ws.Path(config.APIPath)
-	ws.Produces(restful.MIME_JSON)
+	if config.ServeAsYAML {
+		ws.Produces(MIME_YAML)
+	} else {
+		ws.Produces(restful.MIME_JSON)
+	}
 	if !config.DisableCORS {
 		ws.Filter(enableCORS)
 	}
 
 	swagger := BuildSwagger(config)
-	resource := specResource{swagger: swagger}
-	ws.Route(ws.GET("/").To(resource.getSwagger))
+	resource := newSpecResource(specDocument(swagger, config), config.ServeAsYAML)
+	// the spec routes are not part of the spec, should the config include this service
+	ws.Route(ws.GET("/").To(resource.getSwagger).Metadata(KeyOpenAPIDisable, true))
+	if config.SplitByTag {
+		tagged := map[string]specResource{}
+		for tag, each := range splitSwaggerByTag(swagger) {
+			tagged[tag] = newSpecResource(specDocument(each, config), config.ServeAsYAML)
+		}
+		ws.Route(ws.GET("/{tag}").To(func(req *restful.Request, resp *restful.Response) {
+			resource, ok := tagged[req.PathParameter("tag")]
+			if !ok {
+				resp.WriteErrorString(http.StatusNotFound, "no operations with this tag")
+				return
+			}
+			resource.getSwagger(req, resp)
+		}).Metadata(KeyOpenAPIDisable, true))
+	}
 	return ws
 }
 
+// RegisterSwaggerService adds the WebService of NewOpenAPIService to the container, serving the spec on apiPath.
+// If the config has no WebServices, the spec documents those registered with the container so far.
+// The routes of the service are not documented in the spec, see Config.SpecPath.
+func RegisterSwaggerService(container *restful.Container, apiPath string, config Config) {
+	config.APIPath = apiPath
+	config.SpecPath = apiPath
+	if len(config.WebServices) == 0 {
+		config.WebServices = container.RegisteredWebServices()
+	}
+	container.Add(NewOpenAPIService(config))
+}
+
+// specDocument returns the document to serve: the Swagger object or, if the OpenAPIVersion of the config
+// is OpenAPI 3, its conversion, with the paths in the order of Config.SortPaths.
+func specDocument(swagger *spec.Swagger, config Config) interface{} {
+	doc := versionedDocument(swagger, config)
+	if !config.SortPaths {
+		return doc
+	}
+	sorted, err := sortDocumentPaths(doc)
+	if err != nil {
+		warn(config, "sorting paths failed", "error", err)
+		return doc
+	}
+	return sorted
+}
+
+// versionedDocument returns the Swagger object or its conversion to the OpenAPIVersion of the config.
+func versionedDocument(swagger *spec.Swagger, config Config) interface{} {
+	if !isOpenAPI3(config) {
+		return swaggerDocument(swagger)
+	}
+	doc, err := ConvertToOpenAPI3(swagger, config.OpenAPIVersion)
+	if err != nil {
+		warn(config, "conversion to OpenAPI 3 failed", "error", err)
+		return swaggerDocument(swagger)
+	}
+	if version, ok := swagger.Extensions["$schema"]; ok {
+		doc["$schema"] = version
+	}
+	return doc
+}
+
+// swaggerDocument returns the Swagger object or, if it has the $schema extension of SchemaVersionOnRoot,
+// its JSON document with $schema as first key, as the spec package only serializes extensions prefixed by x-.
+func swaggerDocument(swagger *spec.Swagger) interface{} {
+	version, ok := swagger.Extensions["$schema"]
+	if !ok {
+		return swagger
+	}
+	data, err := json.Marshal(swagger)
+	if err != nil {
+		return swagger
+	}
+	value, err := json.Marshal(version)
+	if err != nil {
+		return swagger
+	}
+	doc := append([]byte(`{"$schema":`), value...)
+	if len(data) > len("{}") {
+		doc = append(doc, ',')
+	}
+	return json.RawMessage(append(doc, data[1:]...))
+}
+
+// sortDocumentPaths returns the JSON document with the members of its paths object in the order of pathLess,
+// keeping the order of the other fields, as encoding/json writes map keys in byte order.
+func sortDocumentPaths(doc interface{}) (json.RawMessage, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := orderedFields(data)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, each := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(each.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		if each.key != "paths" {
+			buf.Write(each.value)
+			continue
+		}
+		var paths map[string]json.RawMessage
+		if err := json.Unmarshal(each.value, &paths); err != nil {
+			return nil, fmt.Errorf("paths: %v", err)
+		}
+		names := make([]string, 0, len(paths))
+		for name := range paths {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool { return pathLess(names[i], names[j]) })
+		buf.WriteByte('{')
+		for j, name := range names {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(name)
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(paths[name])
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte('}')
+	return json.RawMessage(buf.Bytes()), nil
+}
+
+type jsonField struct {
+	key   string
+	value json.RawMessage
+}
+
+// orderedFields returns the fields of the JSON object in their order.
+func orderedFields(data []byte) ([]jsonField, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("document is not a JSON object")
+	}
+	var fields []jsonField
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		field := jsonField{key: token.(string)}
+		if err := dec.Decode(&field.value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// pathLess compares the paths segment by segment, ignoring case, and by their bytes if these are equal.
+// The extensions of the paths object, which do not start with a slash, come after the paths.
+func pathLess(a, b string) bool {
+	if isPath, otherIsPath := strings.HasPrefix(a, "/"), strings.HasPrefix(b, "/"); isPath != otherIsPath {
+		return isPath
+	}
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if x, y := strings.ToLower(as[i]), strings.ToLower(bs[i]); x != y {
+			return x < y
+		}
+	}
+	if len(as) != len(bs) {
+		return len(as) < len(bs)
+	}
+	return a < b
+}
+
 // BuildSwagger returns a Swagger object for all services' API endpoints.
+// Errors in the config, see BuildSwaggerWithError, are logged and the invalid parts are ignored.
 func BuildSwagger(config Config) *spec.Swagger {
+	if err := validateConfig(config); err != nil {
+		warn(config, "invalid config", "error", err)
+	}
+	swagger, err := buildSwagger(config)
+	if err != nil {
+		warn(config, "building swagger failed", "error", err)
+	}
+	return swagger
+}
+
+// BuildSwaggerWithError returns a Swagger object for all services' API endpoints,
+// or an error if the config is invalid, e.g. if a key of its GlobalExtensions does not start with x-.
+func BuildSwaggerWithError(config Config) (*spec.Swagger, error) {
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+	swagger, err := buildSwagger(config)
+	if err != nil {
+		return nil, err
+	}
+	return swagger, nil
+}
+
+// validateConfig returns an error for the first problem found in the config.
+func validateConfig(config Config) error {
+	for _, key := range sortedKeys(config.GlobalExtensions) {
+		if !strings.HasPrefix(strings.ToLower(key), ExtensionPrefix) {
+			return fmt.Errorf("global extension %q does not start with %s", key, ExtensionPrefix)
+		}
+	}
+	switch config.OpenAPIVersion {
+	case "", OpenAPIVersion2, OpenAPIVersion3, OpenAPIVersion31:
+	default:
+		return fmt.Errorf("OpenAPI version %q is not %q, %q or %q", config.OpenAPIVersion, OpenAPIVersion2, OpenAPIVersion3, OpenAPIVersion31)
+	}
+	switch config.DecimalFormat {
+	case "", DecimalFormatNumber, DecimalFormatString:
+	default:
+		return fmt.Errorf("decimal format %q is not %q or %q", config.DecimalFormat, DecimalFormatNumber, DecimalFormatString)
+	}
+	if config.PropertySort < SortNone || config.PropertySort > SortByTag {
+		return fmt.Errorf("property sort %d is not SortNone, SortAlpha or SortByTag", config.PropertySort)
+	}
+	switch config.JSONSchemaDraft {
+	case "", JSONSchemaDraft04, JSONSchemaDraft06, JSONSchemaDraft07, JSONSchemaDraft201909, JSONSchemaDraft202012:
+	default:
+		return fmt.Errorf("JSON Schema draft %q is not %q, %q, %q, %q or %q", config.JSONSchemaDraft,
+			JSONSchemaDraft04, JSONSchemaDraft06, JSONSchemaDraft07, JSONSchemaDraft201909, JSONSchemaDraft202012)
+	}
+	if config.NullableStrategy < NullableExtension || config.NullableStrategy > NullableOneOf {
+		return fmt.Errorf("nullable strategy %d is not NullableExtension, NullableKeyword or NullableOneOf", config.NullableStrategy)
+	}
+	if config.NullableStrategy != NullableExtension && nullableStrategy(config) != config.NullableStrategy {
+		// Swagger 2.0 has neither the nullable keyword nor oneOf and OpenAPI 3.0 has no null type
+		return fmt.Errorf("nullable strategy %d is not supported by OpenAPI version %q", config.NullableStrategy, config.OpenAPIVersion)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// buildSwagger returns the Swagger object and an error if the BaseSpec could not be used
+// or, in StrictMode, if there were problems.
+func buildSwagger(config Config) (*spec.Swagger, error) {
+	config.diagnostics = newDiagnostics()
 	// collect paths and model definitions to build Swagger object.
 	paths := &spec.Paths{Paths: map[string]spec.PathItem{}}
 	definitions := spec.Definitions{}
 
 	for _, each := range config.WebServices {
 		for path, item := range buildPaths(each, config).Paths {
-			existingPathItem, ok := paths.Paths[path]
-			if ok {
+			if existingPathItem, ok := paths.Paths[path]; ok {
+				item = existingPathItem
+				addPathItemDescription(&item, each.Documentation())
 				for _, r := range each.Routes() {
-					_, patterns := sanitizePath(r.Path)
-					item = buildPathItem(each, r, existingPathItem, patterns, config)
+					routePath, patterns := sanitizePath(r.Path)
+					if routePath != path || isExcludedRoute(r, config) {
+						continue
+					}
+					item = buildPathItem(each, r, item, patterns, config)
 				}
 			}
 			paths.Paths[path] = item
@@ -43,19 +310,219 @@ func BuildSwagger(config Config) *spec.Swagger {
 			definitions[name] = def
 		}
 	}
+	shareCommonPathParameters(paths)
+	dropUnknownTypeNames(config, definitions)
+	if config.EnableSchemaDeduplication {
+		NewSchemaRegistry().Deduplicate(definitions)
+	}
+	if config.SortDefinitionProperties {
+		sortDefinitionProperties(definitions)
+	}
+	if config.EmitMSEnumExtension {
+		addMSEnumExtensions(definitions)
+	}
+	var describedErr error
+	if config.RequireAllPropertiesDescribed {
+		describedErr = undescribedPropertiesError(definitions, config.RequireDescriptionExclusions)
+	}
+	host, basePath := hostAndBasePath(config)
 	swagger := &spec.Swagger{
 		SwaggerProps: spec.SwaggerProps{
-			Host:        config.Host,
+			Host:        host,
+			BasePath:    basePath,
 			Schemes:     config.Schemes,
 			Swagger:     "2.0",
 			Paths:       paths,
 			Definitions: definitions,
 		},
 	}
+	var baseErr error
+	if config.BaseSpec == nil && config.BaseSpecFS != nil {
+		if base, err := LoadBaseSpecFS(config.BaseSpecFS, config.BaseSpecPath); err != nil {
+			baseErr = fmt.Errorf("load base spec: %v", err)
+		} else {
+			config.BaseSpec = base
+		}
+	}
+	if config.BaseSpec != nil {
+		if base, err := cloneSwagger(config.BaseSpec); err != nil {
+			baseErr = fmt.Errorf("copy base spec: %v", err)
+		} else {
+			swagger = mergeIntoBaseSpec(base, swagger)
+		}
+	}
+	if config.StrictMode {
+		checkDefinitionRefs(config, swagger)
+	}
+	for key, value := range config.GlobalExtensions {
+		if !strings.HasPrefix(strings.ToLower(key), ExtensionPrefix) {
+			continue
+		}
+		setExtension(&swagger.VendorExtensible, key, value)
+	}
+	if config.EmitSchemaVersion != "" && config.SchemaVersionPlacement == SchemaVersionOnRoot {
+		swagger.AddExtension("$schema", config.EmitSchemaVersion)
+	}
+	if config.AllowAdditionalPropertiesOnAll {
+		allowAdditionalProperties(swagger)
+	}
+	if config.Logo != nil {
+		if swagger.Info == nil {
+			swagger.Info = new(spec.Info)
+		}
+		swagger.Info.AddExtension("x-logo", *config.Logo)
+	}
+	if len(config.XTagGroups) > 0 {
+		setExtension(&swagger.VendorExtensible, "x-tagGroups", config.XTagGroups)
+		warnUndefinedGroupTags(swagger, config)
+	}
 	if config.PostBuildSwaggerObjectHandler != nil {
 		config.PostBuildSwaggerObjectHandler(swagger)
 	}
-	return swagger
+	return swagger, config.diagnostics.err(baseErr, describedErr)
+}
+
+// undescribedPropertiesError returns an error listing the properties, as DefinitionName.PropertyName,
+// that have no description. Definitions in the exclusions are not checked.
+func undescribedPropertiesError(definitions spec.Definitions, exclusions []string) error {
+	var missing []string
+	for name, def := range definitions {
+		if containsString(exclusions, name) {
+			continue
+		}
+		for prop, each := range def.Properties {
+			if each.Description == "" {
+				missing = append(missing, name+"."+prop)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return fmt.Errorf("properties without description: %s", strings.Join(missing, ", "))
+}
+
+// allowAdditionalProperties removes additionalProperties false from the definitions and the schemas
+// of the operations, path items, global parameters and global responses of the swagger.
+func allowAdditionalProperties(swagger *spec.Swagger) {
+	allow := func(s *spec.Schema) {
+		if s.AdditionalProperties != nil && !s.AdditionalProperties.Allows && s.AdditionalProperties.Schema == nil {
+			s.AdditionalProperties = nil
+		}
+	}
+	for name, def := range swagger.Definitions {
+		walkSchemas(&def, allow)
+		swagger.Definitions[name] = def
+	}
+	walkOperationSchemas(swagger, func(s *spec.Schema) {
+		walkSchemas(s, allow)
+	})
+}
+
+// setExtension sets the extension keeping the case of its key, unlike AddExtension which lowercases it.
+func setExtension(v *spec.VendorExtensible, key string, value interface{}) {
+	if v.Extensions == nil {
+		v.Extensions = spec.Extensions{}
+	}
+	v.Extensions[key] = value
+}
+
+// warnUndefinedGroupTags warns about each tag of the XTagGroups that is not defined in the tags of the swagger.
+func warnUndefinedGroupTags(swagger *spec.Swagger, config Config) {
+	defined := map[string]bool{}
+	for _, each := range swagger.Tags {
+		defined[each.Name] = true
+	}
+	for _, group := range config.XTagGroups {
+		for _, tag := range group.Tags {
+			if !defined[tag] {
+				warn(config, "tag of x-tagGroups group is not defined in the swagger tags", "group", group.Name, "tag", tag)
+			}
+		}
+	}
+}
+
+// cloneSwagger returns a deep copy of the Swagger object, including all extensions.
+func cloneSwagger(s *spec.Swagger) (*spec.Swagger, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	clone := new(spec.Swagger)
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// mergeIntoBaseSpec merges the generated Swagger object into the base and returns the base.
+// Generated operations, path parameters and definitions replace those of the base; the generated
+// host, basePath and schemes are only used if set.
+func mergeIntoBaseSpec(base, generated *spec.Swagger) *spec.Swagger {
+	base.Swagger = generated.Swagger
+	if generated.Host != "" {
+		base.Host = generated.Host
+	}
+	if generated.BasePath != "" {
+		base.BasePath = generated.BasePath
+	}
+	if len(generated.Schemes) > 0 {
+		base.Schemes = generated.Schemes
+	}
+	if base.Paths == nil {
+		base.Paths = &spec.Paths{}
+	}
+	if base.Paths.Paths == nil {
+		base.Paths.Paths = map[string]spec.PathItem{}
+	}
+	for path, item := range generated.Paths.Paths {
+		existing, ok := base.Paths.Paths[path]
+		if !ok {
+			base.Paths.Paths[path] = item
+			continue
+		}
+		for method, op := range pathItemOperationsByMethod(item) {
+			setPathItemOperation(&existing, method, op)
+		}
+		if len(item.Parameters) > 0 {
+			existing.Parameters = item.Parameters
+		}
+		for key, value := range item.Extensions {
+			setExtension(&existing.VendorExtensible, key, value)
+		}
+		base.Paths.Paths[path] = existing
+	}
+	if base.Definitions == nil {
+		base.Definitions = spec.Definitions{}
+	}
+	for name, def := range generated.Definitions {
+		base.Definitions[name] = def
+	}
+	return base
+}
+
+// hostAndBasePath returns the Host and BasePath of the config, completed from its WebServicesURL.
+func hostAndBasePath(config Config) (host, basePath string) {
+	host, basePath = config.Host, config.BasePath
+	if config.WebServicesURL == "" || (host != "" && basePath != "") {
+		return
+	}
+	rawURL := config.WebServicesURL
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "//" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return
+	}
+	if host == "" {
+		host = u.Host
+	}
+	if basePath == "" {
+		basePath = strings.TrimSuffix(u.Path, "/")
+	}
+	return
 }
 
 func enableCORS(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
@@ -69,10 +536,59 @@ func enableCORS(req *restful.Request, resp *restful.Response, chain *restful.Fil
 }
 
 // specResource is a REST resource to serve the Open-API spec.
+// The spec does not change so its document and ETag are computed once.
 type specResource struct {
-	swagger *spec.Swagger
+	body        []byte
+	contentType string
+	etag        string
+	err         error
+}
+
+func newSpecResource(doc interface{}, asYAML bool) specResource {
+	var s specResource
+	if asYAML {
+		s.contentType = MIME_YAML
+		s.body, s.err = marshalYAML(doc)
+	} else {
+		s.contentType = restful.MIME_JSON
+		if restful.PrettyPrintResponses {
+			s.body, s.err = json.MarshalIndent(doc, "", " ")
+		} else {
+			s.body, s.err = json.Marshal(doc)
+		}
+	}
+	s.etag = etagOf(s.body)
+	return s
+}
+
+// etagOf returns the strong ETag of the document.
+func etagOf(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
 }
 
 func (s specResource) getSwagger(req *restful.Request, resp *restful.Response) {
-	resp.WriteAsJson(s.swagger)
+	if s.err != nil {
+		resp.WriteError(http.StatusInternalServerError, s.err)
+		return
+	}
+	resp.Header().Set("ETag", s.etag)
+	if etagMatches(req.HeaderParameter("If-None-Match"), s.etag) {
+		resp.WriteHeader(http.StatusNotModified)
+		return
+	}
+	resp.Header().Set(restful.HEADER_ContentType, s.contentType)
+	resp.WriteHeader(http.StatusOK)
+	resp.Write(s.body)
+}
+
+// etagMatches returns true if the If-None-Match header value contains the etag, using weak comparison.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, each := range strings.Split(ifNoneMatch, ",") {
+		each = strings.TrimSpace(each)
+		if each == "*" || strings.TrimPrefix(each, "W/") == etag {
+			return true
+		}
+	}
+	return false
 }
@@ -1,10 +1,20 @@
 package restfulspec
 
 import (
+	"encoding/json"
+	"fmt"
+	stdlog "log"
+	"net/http"
 	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	restful "github.com/emicklei/go-restful/v3"
+	"github.com/emicklei/go-restful/v3/log"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
 )
 
 // nolint:paralleltest
@@ -61,3 +71,712 @@ func TestDisablingCORS(t *testing.T) {
 		t.Errorf("The CORS header was set to %s but it was disabled so should not be set", responseHeader)
 	}
 }
+
+// nolint:paralleltest
+func TestRegisterSwaggerService(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/items")
+	ws.Route(ws.GET("/").To(dummy).Writes(Item{}))
+	container := restful.NewContainer()
+	container.Add(ws)
+	RegisterSwaggerService(container, "/apidocs.json", Config{})
+
+	recorder := httptest.NewRecorder()
+	container.Dispatch(recorder, httptest.NewRequest("GET", "/apidocs.json", nil))
+	if got, want := recorder.Code, http.StatusOK; got != want {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	var served spec.Swagger
+	if err := json.Unmarshal(recorder.Body.Bytes(), &served); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := served.Paths.Paths["/items"]; !ok {
+		t.Errorf("missing /items in %v", served.Paths.Paths)
+	}
+
+	// the spec service is not documented when the config includes it
+	swagger := BuildSwagger(Config{WebServices: container.RegisteredWebServices()})
+	if _, ok := swagger.Paths.Paths["/apidocs.json"]; ok {
+		t.Errorf("unexpected /apidocs.json in %v", swagger.Paths.Paths)
+	}
+}
+
+// nolint:paralleltest
+func TestBuildSwaggerMergesPathsOfWebServices(t *testing.T) {
+	ws1 := new(restful.WebService)
+	ws1.Path("/shared")
+	ws1.Route(ws1.GET("").To(dummy))
+
+	ws2 := new(restful.WebService)
+	ws2.Path("/shared")
+	ws2.Route(ws2.DELETE("").To(dummy))
+	ws2.Route(ws2.PUT("").To(dummy).Metadata(KeyOpenAPIInternal, true))
+	ws2.Route(ws2.POST("/other").To(dummy))
+
+	s := BuildSwagger(Config{WebServices: []*restful.WebService{ws1, ws2}})
+	item := s.Paths.Paths["/shared"]
+	if item.Get == nil || item.Delete == nil || item.Put == nil {
+		t.Fatalf("Swagger spec should have methods for GET, DELETE and PUT")
+	}
+	if item.Post != nil {
+		t.Errorf("POST of another path must not be merged")
+	}
+	if got, want := item.Put.Extensions["x-internal"], true; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	s = BuildSwagger(Config{WebServices: []*restful.WebService{ws1, ws2}, StripInternalOperations: true})
+	item = s.Paths.Paths["/shared"]
+	if item.Put != nil {
+		t.Errorf("internal operation should have been stripped")
+	}
+	if item.Delete == nil {
+		t.Errorf("Swagger spec should have method DELETE")
+	}
+}
+
+// nolint:paralleltest
+func TestBuildSwaggerSharesPathParameters(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/users/{user}")
+	ws.Param(ws.PathParameter("user", "user id"))
+	ws.Route(ws.GET("/items/{item}").To(dummy).
+		Param(ws.PathParameter("item", "item id")).
+		Param(ws.QueryParameter("q", "query")))
+	ws.Route(ws.PUT("/items/{item}").To(dummy).
+		Param(ws.PathParameter("item", "another description")))
+	ws.Route(ws.GET("").To(dummy))
+
+	s := BuildSwagger(Config{WebServices: []*restful.WebService{ws}})
+
+	item := s.Paths.Paths["/users/{user}/items/{item}"]
+	if got, want := len(item.Parameters), 1; got != want {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	if got, want := item.Parameters[0].Name, "user"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := len(item.Get.Parameters), 2; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := len(item.Put.Parameters), 1; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	single := s.Paths.Paths["/users/{user}"]
+	if got, want := len(single.Parameters), 0; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := len(single.Get.Parameters), 1; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+// nolint:paralleltest
+func TestBuildSwaggerPathItemDescription(t *testing.T) {
+	ws1 := new(restful.WebService)
+	ws1.Path("/shared").Doc("first service")
+	ws1.Route(ws1.GET("").To(dummy))
+	ws1.Route(ws1.GET("/one").To(dummy))
+
+	ws2 := new(restful.WebService)
+	ws2.Path("/shared").Doc("second service")
+	ws2.Route(ws2.DELETE("").To(dummy))
+
+	s := BuildSwagger(Config{WebServices: []*restful.WebService{ws1, ws2}})
+
+	if got, want := s.Paths.Paths["/shared"].Extensions["x-description"], "first service\nsecond service"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := s.Paths.Paths["/shared/one"].Extensions["x-description"], "first service"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+// nolint:paralleltest
+func TestBuildSwaggerSortDefinitionProperties(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/sorted").To(dummy).Writes(Item{}))
+	cfg := Config{
+		WebServices: []*restful.WebService{ws},
+		DefinitionPostProcessor: func(name string, schema *spec.Schema, st reflect.Type) {
+			zebra, alpha := spec.StringProperty(), spec.StringProperty()
+			zebra.AddExtension("x-order", "0")
+			alpha.AddExtension("x-order", "1")
+			schema.Properties["zebra"] = *zebra
+			schema.Properties["alpha"] = *alpha
+			schema.Required = []string{"zebra", "alpha"}
+		},
+	}
+
+	unsorted, _ := json.Marshal(BuildSwagger(cfg).Definitions["restfulspec.Item"])
+	if !strings.Contains(string(unsorted), `"properties":{"zebra"`) {
+		t.Errorf("expected x-order to be honored, got %s", unsorted)
+	}
+
+	cfg.SortDefinitionProperties = true
+	sorted, _ := json.Marshal(BuildSwagger(cfg).Definitions["restfulspec.Item"])
+	if !strings.Contains(string(sorted), `"required":["alpha","zebra"],"properties":{"alpha"`) {
+		t.Errorf("expected sorted properties, got %s", sorted)
+	}
+	if strings.Contains(string(sorted), "x-order") {
+		t.Errorf("expected x-order to be removed, got %s", sorted)
+	}
+}
+
+// nolint:paralleltest
+func TestBuildSwaggerPathsAreSorted(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/zebra").To(dummy))
+	ws.Route(ws.GET("/alpha/b").To(dummy))
+	ws.Route(ws.GET("/alpha").To(dummy))
+
+	data, err := json.Marshal(BuildSwagger(Config{WebServices: []*restful.WebService{ws}}).Paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alpha := strings.Index(string(data), `"/alpha"`)
+	alphaB := strings.Index(string(data), `"/alpha/b"`)
+	zebra := strings.Index(string(data), `"/zebra"`)
+	if !(alpha < alphaB && alphaB < zebra) {
+		t.Errorf("paths are not serialized in alphabetical order: %s", data)
+	}
+}
+
+// nolint:paralleltest
+func TestServeAsYAML(t *testing.T) {
+	sample := new(restful.WebService)
+	sample.Path("/samples")
+	sample.Route(sample.GET("/{id}").To(dummy).Returns(200, "ok", Sample{}))
+	ws := NewOpenAPIService(Config{APIPath: "/apidocs.yaml", ServeAsYAML: true, WebServices: []*restful.WebService{sample}})
+	wc := restful.NewContainer().Add(ws)
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/apidocs.yaml", nil)
+
+	wc.Dispatch(recorder, request)
+
+	if got := recorder.Result().Header.Get(restful.HEADER_ContentType); got != MIME_YAML {
+		t.Errorf("got content type %q", got)
+	}
+	doc, err := swag.BytesToYAMLDoc(recorder.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := swag.YAMLToJSON(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var swagger spec.Swagger
+	if err := json.Unmarshal(data, &swagger); err != nil {
+		t.Fatal(err)
+	}
+	if swagger.Swagger != "2.0" {
+		t.Errorf("got swagger version %q", swagger.Swagger)
+	}
+	if _, ok := swagger.Paths.Paths["/samples/{id}"].Get.Responses.StatusCodeResponses[200]; !ok {
+		t.Errorf("missing response in %s", recorder.Body.String())
+	}
+	if _, ok := swagger.Definitions["restfulspec.Sample"]; !ok {
+		t.Errorf("missing definition in %s", recorder.Body.String())
+	}
+}
+
+// nolint:paralleltest
+func TestSpecETag(t *testing.T) {
+	wc := restful.NewContainer().Add(NewOpenAPIService(Config{APIPath: "/apidocs.json"}))
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest("GET", "/apidocs.json", nil)
+		if ifNoneMatch != "" {
+			request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		wc.Dispatch(recorder, request)
+		return recorder
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	if first.Code != 200 || etag == "" || first.Body.Len() == 0 {
+		t.Fatalf("got status %d, etag %q and %d bytes", first.Code, etag, first.Body.Len())
+	}
+	for _, each := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		if got := get(each); got.Code != 304 || got.Body.Len() != 0 || got.Header().Get("ETag") != etag {
+			t.Errorf("If-None-Match %s: got status %d and %d bytes", each, got.Code, got.Body.Len())
+		}
+	}
+	if got := get(`"other"`); got.Code != 200 || got.Body.String() != first.Body.String() {
+		t.Errorf("got status %d", got.Code)
+	}
+}
+
+// nolint:paralleltest
+func TestBuildSwaggerHostAndBasePath(t *testing.T) {
+	for _, each := range []struct {
+		config         Config
+		host, basePath string
+	}{
+		{Config{BasePath: "/api/v1"}, "", "/api/v1"},
+		{Config{Host: "example.com", BasePath: "/api"}, "example.com", "/api"},
+		{Config{WebServicesURL: "http://localhost:8080/api/v1/"}, "localhost:8080", "/api/v1"},
+		{Config{WebServicesURL: "localhost:8080"}, "localhost:8080", ""},
+		{Config{WebServicesURL: "http://localhost:8080/api", Host: "example.com"}, "example.com", "/api"},
+		{Config{WebServicesURL: "http://localhost:8080/api", BasePath: "/v2"}, "localhost:8080", "/v2"},
+	} {
+		s := BuildSwagger(each.config)
+		if s.Host != each.host || s.BasePath != each.basePath {
+			t.Errorf("%+v: got host %q and basePath %q", each.config, s.Host, s.BasePath)
+		}
+	}
+}
+
+// nolint:paralleltest
+func TestBuildSwaggerLogo(t *testing.T) {
+	s := BuildSwagger(Config{
+		Logo: &LogoInfo{URL: "https://example.com/logo.png", AltText: "Example", BackgroundColor: "#FFFFFF"},
+		PostBuildSwaggerObjectHandler: func(s *spec.Swagger) {
+			if _, ok := s.Info.Extensions["x-logo"]; !ok {
+				t.Errorf("handler is called without x-logo")
+			}
+			s.Info.Title = "Example API"
+		},
+	})
+	data, err := json.Marshal(s.Info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"title":"Example API","x-logo":{"url":"https://example.com/logo.png","altText":"Example","backgroundColor":"#FFFFFF"}}`
+	if string(data) != expected {
+		t.Errorf("got %s want %s", data, expected)
+	}
+
+	if s := BuildSwagger(Config{}); s.Info != nil {
+		t.Errorf("got info %v without logo", s.Info)
+	}
+}
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Print(v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprint(v...))
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+// nolint:paralleltest
+func TestBuildSwaggerTagGroups(t *testing.T) {
+	logger := new(recordingLogger)
+	log.SetLogger(logger)
+	defer log.SetLogger(stdlog.New(os.Stderr, "[restful] ", stdlog.LstdFlags|stdlog.Lshortfile))
+
+	s := BuildSwagger(Config{
+		XTagGroups: []TagGroup{{Name: "Users", Tags: []string{"users", "groups"}}},
+		BaseSpec: &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+			Tags: []spec.Tag{{TagProps: spec.TagProps{Name: "users"}}},
+		}},
+		PostBuildSwaggerObjectHandler: func(s *spec.Swagger) {
+			if _, ok := s.Extensions["x-tagGroups"]; !ok {
+				t.Errorf("handler is called without x-tagGroups")
+			}
+		},
+	})
+	data, err := json.Marshal(s.Extensions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"x-tagGroups":[{"name":"Users","tags":["users","groups"]}]}`; string(data) != expected {
+		t.Errorf("got %s want %s", data, expected)
+	}
+	if len(logger.lines) != 1 || !strings.Contains(logger.lines[0], `"groups"`) {
+		t.Errorf("got warnings %v", logger.lines)
+	}
+}
+
+type Paint struct {
+	Finish string   `json:"finish" enum:"matte|gloss"`
+	Colors []string `json:"colors"`
+}
+
+// nolint:paralleltest
+func TestBuildSwaggerMSEnumExtension(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/paints").To(dummy).
+		Param(ws.QueryParameter("color", "color of the paint").PossibleValues([]string{"red", "blue"}).AddExtension("$ref", "Color")).
+		Returns(200, "ok", Paint{}))
+	s := BuildSwagger(Config{
+		WebServices:         []*restful.WebService{ws},
+		EmitMSEnumExtension: true,
+		DefinitionPostProcessor: func(name string, schema *spec.Schema, t reflect.Type) {
+			if colors, ok := schema.Properties["colors"]; ok {
+				colors.Items.Schema.Enum = []interface{}{"red", "blue"}
+				schema.Properties["colors"] = colors
+			}
+		},
+	})
+
+	for _, each := range []struct {
+		schema spec.Schema
+		ext    string
+	}{
+		{s.Definitions["Color"], `{"modelAsString":false,"name":"Color"}`},
+		{s.Definitions["restfulspec.Paint"].Properties["finish"], `{"modelAsString":false,"name":"finish"}`},
+		{*s.Definitions["restfulspec.Paint"].Properties["colors"].Items.Schema, `{"modelAsString":false,"name":"colors"}`},
+	} {
+		data, err := json.Marshal(each.schema.Extensions["x-ms-enum"])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != each.ext {
+			t.Errorf("got %s want %s", data, each.ext)
+		}
+	}
+	if _, ok := s.Definitions["restfulspec.Paint"].Extensions["x-ms-enum"]; ok {
+		t.Errorf("x-ms-enum on a schema without enum")
+	}
+}
+
+// nolint:paralleltest
+func TestBuildSwaggerSchemaVersion(t *testing.T) {
+	const draft07 = "http://json-schema.org/draft-07/schema#"
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/samples").To(dummy).Returns(200, "ok", Sample{}))
+
+	s := BuildSwagger(Config{WebServices: []*restful.WebService{ws}, EmitSchemaVersion: draft07})
+	data, err := json.Marshal(s.Definitions["restfulspec.Sample"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"$schema":"`+draft07+`"`) {
+		t.Errorf("missing $schema in %s", data)
+	}
+	if _, ok := s.Extensions["$schema"]; ok {
+		t.Errorf("unexpected $schema on root")
+	}
+
+	s = BuildSwagger(Config{WebServices: []*restful.WebService{ws}, EmitSchemaVersion: draft07, SchemaVersionPlacement: SchemaVersionOnRoot})
+	if got := s.Extensions["$schema"]; got != draft07 {
+		t.Errorf("got root $schema %v", got)
+	}
+	if got := s.Definitions["restfulspec.Sample"].Schema; got != "" {
+		t.Errorf("got definition $schema %v", got)
+	}
+
+	for _, version := range []string{OpenAPIVersion2, OpenAPIVersion3} {
+		container := restful.NewContainer()
+		container.Add(NewOpenAPIService(Config{WebServices: []*restful.WebService{ws}, APIPath: "/apidocs.json",
+			EmitSchemaVersion: draft07, SchemaVersionPlacement: SchemaVersionOnRoot, OpenAPIVersion: version}))
+		rec := httptest.NewRecorder()
+		container.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/apidocs.json", nil))
+		var doc map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+			t.Fatal(err)
+		}
+		if got := doc["$schema"]; got != draft07 {
+			t.Errorf("%s: got served $schema %v in %s", version, got, rec.Body.String())
+		}
+		if _, ok := doc["paths"]; !ok {
+			t.Errorf("%s: missing paths in %s", version, rec.Body.String())
+		}
+	}
+	buf := new(strings.Builder)
+	if err := WriteSwaggerJSON(buf, s); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "{\n  \"$schema\": \""+draft07+"\",\n  \"swagger\"") {
+		t.Errorf("missing $schema in %s", buf.String())
+	}
+}
+
+// nolint:paralleltest
+func TestBuildSwaggerGlobalExtensions(t *testing.T) {
+	extensions := map[string]interface{}{
+		"x-amazon-apigateway-binary-media-types": []string{"image/png"},
+		"x-kong-plugin-cors":                     map[string]interface{}{"enabled": true},
+	}
+	s, err := BuildSwaggerWithError(Config{GlobalExtensions: extensions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(s.Extensions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"x-amazon-apigateway-binary-media-types":["image/png"],"x-kong-plugin-cors":{"enabled":true}}`
+	if string(data) != expected {
+		t.Errorf("got %s want %s", data, expected)
+	}
+
+	extensions["info"] = "not an extension"
+	if _, err := BuildSwaggerWithError(Config{GlobalExtensions: extensions}); err == nil || !strings.Contains(err.Error(), `"info"`) {
+		t.Errorf("got error %v", err)
+	}
+
+	logger := new(recordingLogger)
+	log.SetLogger(logger)
+	defer log.SetLogger(stdlog.New(os.Stderr, "[restful] ", stdlog.LstdFlags|stdlog.Lshortfile))
+	s = BuildSwagger(Config{GlobalExtensions: extensions})
+	if _, ok := s.Extensions["info"]; ok || len(s.Extensions) != 2 {
+		t.Errorf("got extensions %v", s.Extensions)
+	}
+	if len(logger.lines) != 1 {
+		t.Errorf("got warnings %v", logger.lines)
+	}
+}
+
+const specWithExtensions = `{
+  "swagger": "2.0",
+  "x-tool": "stoplight",
+  "info": {"title": "Loaded", "version": "1", "x-audience": "partners"},
+  "paths": {
+    "/legacy": {
+      "x-owner": "team-a",
+      "get": {
+        "x-codegen-request-body-name": "body",
+        "parameters": [{"name": "q", "in": "query", "type": "string", "x-Example": "all"}],
+        "responses": {"200": {"description": "ok", "x-cache": 60}}
+      }
+    }
+  },
+  "definitions": {
+    "Legacy": {
+      "type": "object",
+      "x-go-package": "legacy",
+      "properties": {"id": {"type": "string", "x-nullable": true}}
+    }
+  }
+}`
+
+// nolint:paralleltest
+func TestBuildSwaggerPreservesLoadedExtensions(t *testing.T) {
+	var loaded spec.Swagger
+	if err := json.Unmarshal([]byte(specWithExtensions), &loaded); err != nil {
+		t.Fatal(err)
+	}
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/samples").To(dummy).Returns(200, "ok", Sample{}))
+
+	s := BuildSwagger(Config{
+		WebServices: []*restful.WebService{ws},
+		Logo:        &LogoInfo{URL: "logo.png"},
+		XTagGroups:  []TagGroup{{Name: "All", Tags: []string{}}},
+		PostBuildSwaggerObjectHandler: func(s *spec.Swagger) {
+			s.Info.InfoProps = loaded.Info.InfoProps
+			for key, value := range loaded.Info.Extensions {
+				setExtension(&s.Info.VendorExtensible, key, value)
+			}
+			for key, value := range loaded.Extensions {
+				setExtension(&s.VendorExtensible, key, value)
+			}
+			for path, item := range loaded.Paths.Paths {
+				s.Paths.Paths[path] = item
+			}
+			for name, def := range loaded.Definitions {
+				s.Definitions[name] = def
+			}
+		},
+	})
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var output spec.Swagger
+	if err := json.Unmarshal(data, &output); err != nil {
+		t.Fatal(err)
+	}
+
+	item := output.Paths.Paths["/legacy"]
+	def := output.Definitions["Legacy"]
+	for _, each := range []struct {
+		extensions spec.Extensions
+		key        string
+		want       interface{}
+	}{
+		{output.Extensions, "x-tool", "stoplight"},
+		{output.Info.Extensions, "x-audience", "partners"},
+		{item.Extensions, "x-owner", "team-a"},
+		{item.Get.Extensions, "x-codegen-request-body-name", "body"},
+		{item.Get.Parameters[0].Extensions, "x-Example", "all"},
+		{item.Get.Responses.StatusCodeResponses[200].Extensions, "x-cache", float64(60)},
+		{def.Extensions, "x-go-package", "legacy"},
+		{def.Properties["id"].Extensions, "x-nullable", true},
+	} {
+		if got := each.extensions[each.key]; got != each.want {
+			t.Errorf("%s: got %v want %v", each.key, got, each.want)
+		}
+	}
+	if _, ok := output.Info.Extensions["x-logo"]; !ok {
+		t.Errorf("missing x-logo next to loaded info extensions")
+	}
+	if _, ok := output.Extensions["x-tagGroups"]; !ok {
+		t.Errorf("missing x-tagGroups next to loaded extensions")
+	}
+}
+
+// nolint:paralleltest
+func TestBuildSwaggerBaseSpec(t *testing.T) {
+	var base spec.Swagger
+	if err := json.Unmarshal([]byte(`{
+  "swagger": "2.0",
+  "host": "api.example.com",
+  "info": {"title": "Base", "version": "1"},
+  "tags": [{"name": "samples"}],
+  "securityDefinitions": {"key": {"type": "apiKey", "name": "X-Key", "in": "header"}},
+  "paths": {
+    "/samples": {
+      "x-owner": "team-a",
+      "get": {"summary": "hand-written", "responses": {"200": {"description": "ok"}}},
+      "delete": {"summary": "hand-written", "responses": {"204": {"description": "deleted"}}}
+    },
+    "/health": {"get": {"responses": {"200": {"description": "ok"}}}}
+  },
+  "definitions": {
+    "restfulspec.Sample": {"type": "object", "description": "hand-written"},
+    "Health": {"type": "object"}
+  }
+}`), &base); err != nil {
+		t.Fatal(err)
+	}
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/samples").To(dummy).Doc("generated").Returns(200, "ok", Sample{}))
+
+	s := BuildSwagger(Config{WebServices: []*restful.WebService{ws}, BaseSpec: &base})
+
+	if s.Info == nil || s.Info.Title != "Base" || s.Host != "api.example.com" || len(s.Tags) != 1 || len(s.SecurityDefinitions) != 1 {
+		t.Errorf("parts of the base spec are missing: %s", asJSON(s))
+	}
+	samples := s.Paths.Paths["/samples"]
+	if got, want := samples.Get.Summary, "generated"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if samples.Delete == nil || samples.Extensions["x-owner"] != "team-a" {
+		t.Errorf("base operation or extension is missing: %s", asJSON(samples))
+	}
+	if _, ok := s.Paths.Paths["/health"]; !ok {
+		t.Errorf("base path is missing")
+	}
+	if got := s.Definitions["restfulspec.Sample"].Description; got == "hand-written" {
+		t.Errorf("generated definition does not take precedence")
+	}
+	if _, ok := s.Definitions["Health"]; !ok {
+		t.Errorf("base definition is missing")
+	}
+
+	if got := base.Paths.Paths["/samples"].Get.Summary; got != "hand-written" {
+		t.Errorf("base spec was modified")
+	}
+	if _, ok := base.Definitions["restfulspec.Item"]; ok {
+		t.Errorf("base spec was modified")
+	}
+}
+
+type Invitation struct {
+	Email   string `description:"address to send the invitation to"`
+	Message string
+	Sender  Publisher `description:"who invites"`
+}
+
+func TestRequireAllPropertiesDescribed(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Route(ws.POST("/invitations").To(dummy).Reads(Invitation{}))
+	config := Config{WebServices: []*restful.WebService{ws}, RequireAllPropertiesDescribed: true}
+
+	_, err := BuildSwaggerWithError(config)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got, want := err.Error(), "properties without description: restfulspec.Invitation.Message, restfulspec.Publisher.Name"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	config.RequireDescriptionExclusions = []string{"restfulspec.Publisher"}
+	_, err = BuildSwaggerWithError(config)
+	if got, want := fmt.Sprint(err), "properties without description: restfulspec.Invitation.Message"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	config.RequireDescriptionExclusions = append(config.RequireDescriptionExclusions, "restfulspec.Invitation")
+	if _, err := BuildSwaggerWithError(config); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestAllowAdditionalPropertiesOnAll(t *testing.T) {
+	var base spec.Swagger
+	if err := json.Unmarshal([]byte(`{
+  "swagger": "2.0",
+  "paths": {
+    "/health": {"get": {"responses": {"200": {"description": "ok", "schema": {"type": "object", "additionalProperties": false}}}}}
+  },
+  "definitions": {
+    "Health": {"type": "object", "additionalProperties": false, "properties": {"checks": {"type": "object", "additionalProperties": false}}},
+    "Labels": {"type": "object", "additionalProperties": {"type": "string"}}
+  }
+}`), &base); err != nil {
+		t.Fatal(err)
+	}
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/samples").To(dummy).Returns(200, "ok", Sample{}))
+	config := Config{WebServices: []*restful.WebService{ws}, BaseSpec: &base,
+		DefinitionPostProcessor: func(name string, schema *spec.Schema, t reflect.Type) {
+			schema.AdditionalProperties = &spec.SchemaOrBool{Allows: false}
+		}}
+
+	if data, _ := json.Marshal(BuildSwagger(config)); !strings.Contains(string(data), `"additionalProperties":false`) {
+		t.Fatalf("expected additionalProperties false without AllowAdditionalPropertiesOnAll: %s", data)
+	}
+	config.AllowAdditionalPropertiesOnAll = true
+	s := BuildSwagger(config)
+	if data, _ := json.Marshal(s); strings.Contains(string(data), `"additionalProperties":false`) {
+		t.Errorf("got %s", data)
+	}
+	if labels := s.Definitions["Labels"]; labels.AdditionalProperties == nil || labels.AdditionalProperties.Schema == nil {
+		t.Errorf("additionalProperties schema should be kept: %s", asJSON(labels))
+	}
+}
+
+func TestSortPaths(t *testing.T) {
+	ws := new(restful.WebService)
+	for _, path := range []string{"/pets-archive", "/Zoo", "/pets/{id}", "/apple", "/pets"} {
+		ws.Route(ws.GET(path).To(dummy))
+	}
+	want := []string{"/apple", "/pets", "/pets/{id}", "/pets-archive", "/Zoo"}
+	unsorted := asJSON(specDocument(BuildSwagger(Config{WebServices: []*restful.WebService{ws}}), Config{}))
+	if strings.Index(unsorted, `"/Zoo"`) > strings.Index(unsorted, `"/apple"`) {
+		t.Errorf("paths are not in byte order without SortPaths: %s", unsorted)
+	}
+	for name, config := range map[string]Config{
+		"json":    {SortPaths: true},
+		"yaml":    {SortPaths: true, ServeAsYAML: true},
+		"openapi": {SortPaths: true, OpenAPIVersion: OpenAPIVersion3},
+		"$schema": {SortPaths: true, EmitSchemaVersion: "http://json-schema.org/draft-04/schema#", SchemaVersionPlacement: SchemaVersionOnRoot},
+	} {
+		config.WebServices = []*restful.WebService{ws}
+		config.APIPath = "/apidocs"
+		container := restful.NewContainer()
+		container.Add(NewOpenAPIService(config))
+		rec := httptest.NewRecorder()
+		container.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/apidocs", nil))
+		body := rec.Body.String()
+		last := -1
+		for _, path := range want {
+			quoted := `"` + path + `"`
+			if config.ServeAsYAML {
+				quoted = path + ":"
+			}
+			i := strings.Index(body, quoted)
+			if i <= last {
+				t.Errorf("%s: %s is not after the previous path in %s", name, path, body)
+			}
+			last = i
+		}
+		if name == "$schema" && !strings.HasPrefix(body, `{
+ "$schema"`) {
+			t.Errorf("%s: $schema is not the first field of %s", name, body)
+		}
+		if !strings.Contains(body, "swagger") && !strings.Contains(body, "openapi") {
+			t.Errorf("%s: missing version in %s", name, body)
+		}
+	}
+}
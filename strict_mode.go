@@ -0,0 +1,134 @@
+package restfulspec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// AggregateError holds all problems found while building a Swagger object in Config.StrictMode.
+type AggregateError struct {
+	Errors []error
+}
+
+func (e *AggregateError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, each := range e.Errors {
+		messages[i] = each.Error()
+	}
+	return fmt.Sprintf("%d problem(s) building swagger: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the problems, for errors.Is and errors.As.
+func (e *AggregateError) Unwrap() []error {
+	return e.Errors
+}
+
+// diagnostics collects the problems of a single build.
+type diagnostics struct {
+	// errors holds the warnings in StrictMode
+	errors []error
+	// definitionTypes holds the Go type of each definition name to detect collisions
+	definitionTypes map[string]reflect.Type
+	// marshalers holds the json.Marshaler types that were warned about
+	marshalers map[reflect.Type]bool
+}
+
+func newDiagnostics() *diagnostics {
+	return &diagnostics{definitionTypes: map[string]reflect.Type{}, marshalers: map[reflect.Type]bool{}}
+}
+
+// record returns true if the warning is kept as an error of the build instead of being logged.
+func (d *diagnostics) record(cfg Config, msg string, fields []interface{}) bool {
+	if d == nil || !cfg.StrictMode {
+		return false
+	}
+	d.errors = append(d.errors, errors.New(formatLogMessage(msg, fields)))
+	return true
+}
+
+// err returns the AggregateError of the problems and the other errors. If there are no problems,
+// it returns the other error if there is only one.
+func (d *diagnostics) err(others ...error) error {
+	var all []error
+	if d != nil {
+		all = append(all, d.errors...)
+	}
+	for _, each := range others {
+		if each != nil {
+			all = append(all, each)
+		}
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	if len(all) == 1 && (d == nil || len(d.errors) == 0) {
+		return all[0]
+	}
+	return &AggregateError{Errors: all}
+}
+
+// checkDefinitionType warns if the definition name is already used by another Go type.
+func checkDefinitionType(cfg Config, name string, t reflect.Type) {
+	d := cfg.diagnostics
+	if d == nil {
+		return
+	}
+	existing, ok := d.definitionTypes[name]
+	if !ok {
+		d.definitionTypes[name] = t
+		return
+	}
+	if existing != t {
+		warn(cfg, "definition name is used by more than one type", "definition", name,
+			"types", existing.PkgPath()+"."+existing.Name()+", "+t.PkgPath()+"."+t.Name())
+	}
+}
+
+// warnUndocumentedMarshaler warns that the json.Marshaler type, or the type it points to, is documented as any value,
+// once per build for the properties and the definition of the type.
+func warnUndocumentedMarshaler(cfg Config, t reflect.Type, fields ...interface{}) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if d := cfg.diagnostics; d != nil {
+		if d.marshalers[t] {
+			return
+		}
+		d.marshalers[t] = true
+	}
+	warn(cfg, "type implements json.Marshaler and has no JSONMarshalerOverrides schema, it is documented as any value", fields...)
+}
+
+// checkDefinitionRefs warns about each reference to a definition that does not exist,
+// e.g. for fields of a type that has no schema such as func or chan.
+func checkDefinitionRefs(cfg Config, swagger *spec.Swagger) {
+	missing := map[string]bool{}
+	check := func(s *spec.Schema) {
+		walkSchemaRefs(s, func(ref string) {
+			name := strings.TrimPrefix(ref, definitionRoot)
+			if name == ref {
+				return
+			}
+			if _, ok := swagger.Definitions[name]; !ok {
+				missing[name] = true
+			}
+		})
+	}
+	walkOperationSchemas(swagger, check)
+	for _, def := range swagger.Definitions {
+		check(&def)
+	}
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		warn(cfg, "reference to a definition that does not exist", "definition", name)
+	}
+}
@@ -0,0 +1,75 @@
+package restfulspec
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+)
+
+type Subscriber struct {
+	Name     string
+	Callback func(string)
+	Topic    int32 `protobuf:"varint,3,opt,name=topic,proto3,enum=tests.Unregistered" json:"topic,omitempty"`
+}
+
+type Publisher struct {
+	Name string
+}
+
+type Broker struct {
+	Name string
+	Port int
+}
+
+func strictModeWebService() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Route(ws.POST("/subscribers").To(dummy).Reads(Subscriber{}))
+	ws.Route(ws.POST("/publishers").To(dummy).Reads(Publisher{}))
+	ws.Route(ws.POST("/brokers").To(dummy).Reads(Broker{}))
+	return ws
+}
+
+func TestStrictMode(t *testing.T) {
+	config := Config{
+		WebServices: []*restful.WebService{strictModeWebService()},
+		ModelTypeNameHandler: func(t reflect.Type) (string, bool) {
+			if t.Name() == "Publisher" || t.Name() == "Broker" {
+				return "Endpoint", true
+			}
+			return "", false
+		},
+		Logger: NopLogger{},
+	}
+	if _, err := BuildSwaggerWithError(config); err != nil {
+		t.Fatalf("unexpected error without StrictMode: %v", err)
+	}
+
+	config.StrictMode = true
+	swagger, err := BuildSwaggerWithError(config)
+	if swagger != nil {
+		t.Error("expected no swagger in StrictMode")
+	}
+	var aggregate *AggregateError
+	if !errors.As(err, &aggregate) {
+		t.Fatalf("got error %v", err)
+	}
+	if got, want := len(aggregate.Errors), 3; got != want {
+		t.Fatalf("got %d errors want %d: %v", got, want, err)
+	}
+	for _, want := range []string{`enum="tests.Unregistered"`, `definition="Endpoint"`, `definition="restfulspec.Subscriber.Callback"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("missing %s in %v", want, err)
+		}
+	}
+}
+
+func TestStrictModeWithoutProblems(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Route(ws.POST("/publishers").To(dummy).Reads(Publisher{}).Returns(200, "ok", []Broker{}))
+	if _, err := BuildSwaggerWithError(Config{WebServices: []*restful.WebService{ws}, StrictMode: true}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,304 @@
+package restfulspec
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// ChangeKind tells whether a SwaggerChange can break existing clients.
+type ChangeKind int
+
+const (
+	// NonBreaking changes, such as added paths and optional parameters, are compatible with existing clients.
+	NonBreaking ChangeKind = iota
+	// Breaking changes, such as removed paths and parameters, narrowed enums and changed types, may break existing clients.
+	Breaking
+)
+
+func (k ChangeKind) String() string {
+	if k == Breaking {
+		return "breaking"
+	}
+	return "non-breaking"
+}
+
+// SwaggerChange describes a single difference between two Swagger documents.
+type SwaggerChange struct {
+	Kind ChangeKind
+	// Location of the change, e.g. "GET /users/{id} parameter query:name" or "definition User property name"
+	Location string
+	Message  string
+}
+
+func (c SwaggerChange) String() string {
+	return fmt.Sprintf("[%s] %s: %s", c.Kind, c.Location, c.Message)
+}
+
+// DiffSwagger returns the changes between the documents a (old) and b (new), sorted by location, kind and message.
+// A nil document is compared as an empty one.
+func DiffSwagger(a, b *spec.Swagger) []SwaggerChange {
+	if a == nil {
+		a = new(spec.Swagger)
+	}
+	if b == nil {
+		b = new(spec.Swagger)
+	}
+	d := swaggerDiff{}
+	d.diffPaths(pathsOf(a), pathsOf(b))
+	d.diffDefinitions(a.Definitions, b.Definitions)
+	sort.Slice(d.changes, func(i, j int) bool {
+		ci, cj := d.changes[i], d.changes[j]
+		if ci.Location != cj.Location {
+			return ci.Location < cj.Location
+		}
+		if ci.Kind != cj.Kind {
+			return ci.Kind < cj.Kind
+		}
+		return ci.Message < cj.Message
+	})
+	return d.changes
+}
+
+// HasBreakingChanges returns true if any of the changes is Breaking.
+func HasBreakingChanges(changes []SwaggerChange) bool {
+	for _, each := range changes {
+		if each.Kind == Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+type swaggerDiff struct {
+	changes []SwaggerChange
+}
+
+func (d *swaggerDiff) add(kind ChangeKind, location, format string, args ...interface{}) {
+	d.changes = append(d.changes, SwaggerChange{Kind: kind, Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+func pathsOf(s *spec.Swagger) map[string]spec.PathItem {
+	if s.Paths == nil {
+		return map[string]spec.PathItem{}
+	}
+	return s.Paths.Paths
+}
+
+func (d *swaggerDiff) diffPaths(a, b map[string]spec.PathItem) {
+	for path, itemA := range a {
+		itemB, ok := b[path]
+		if !ok {
+			d.add(Breaking, path, "path removed")
+			continue
+		}
+		opsA, opsB := pathItemOperationsByMethod(itemA), pathItemOperationsByMethod(itemB)
+		for method, opA := range opsA {
+			location := method + " " + path
+			opB, ok := opsB[method]
+			if !ok {
+				d.add(Breaking, location, "operation removed")
+				continue
+			}
+			d.diffParameters(location, operationParameters(itemA, opA), operationParameters(itemB, opB))
+			d.diffResponses(location, opA.Responses, opB.Responses)
+		}
+		for method := range opsB {
+			if _, ok := opsA[method]; !ok {
+				d.add(NonBreaking, method+" "+path, "operation added")
+			}
+		}
+	}
+	for path := range b {
+		if _, ok := a[path]; !ok {
+			d.add(NonBreaking, path, "path added")
+		}
+	}
+}
+
+// operationParameters returns the parameters of the operation including those of its path item.
+func operationParameters(item spec.PathItem, op *spec.Operation) []spec.Parameter {
+	params := make([]spec.Parameter, 0, len(item.Parameters)+len(op.Parameters))
+	params = append(params, item.Parameters...)
+	return append(params, op.Parameters...)
+}
+
+func parametersByKey(params []spec.Parameter) map[string]spec.Parameter {
+	keyed := make(map[string]spec.Parameter, len(params))
+	for _, each := range params {
+		keyed[each.In+":"+each.Name] = each
+	}
+	return keyed
+}
+
+func (d *swaggerDiff) diffParameters(location string, a, b []spec.Parameter) {
+	paramsA, paramsB := parametersByKey(a), parametersByKey(b)
+	for key, pA := range paramsA {
+		at := location + " parameter " + key
+		pB, ok := paramsB[key]
+		if !ok {
+			d.add(Breaking, at, "parameter removed")
+			continue
+		}
+		if !pA.Required && pB.Required {
+			d.add(Breaking, at, "parameter became required")
+		} else if pA.Required && !pB.Required {
+			d.add(NonBreaking, at, "parameter became optional")
+		}
+		if typeA, typeB := parameterType(pA), parameterType(pB); typeA != typeB {
+			d.add(Breaking, at, "type changed from %s to %s", typeA, typeB)
+		}
+		d.diffEnum(at, pA.Enum, pB.Enum)
+	}
+	for key, pB := range paramsB {
+		if _, ok := paramsA[key]; ok {
+			continue
+		}
+		if pB.Required {
+			d.add(Breaking, location+" parameter "+key, "required parameter added")
+		} else {
+			d.add(NonBreaking, location+" parameter "+key, "optional parameter added")
+		}
+	}
+}
+
+func parameterType(p spec.Parameter) string {
+	if p.Schema != nil {
+		return schemaType(p.Schema)
+	}
+	if p.Items != nil {
+		return p.Type + " of " + p.Items.Type
+	}
+	return p.Type
+}
+
+// schemaType returns a description of the type of the schema that can be compared.
+func schemaType(s *spec.Schema) string {
+	if s == nil {
+		return "none"
+	}
+	if ref := s.Ref.String(); ref != "" {
+		return ref
+	}
+	t := strings.Join(s.Type, ",")
+	if s.Items != nil && s.Items.Schema != nil {
+		t += " of " + schemaType(s.Items.Schema)
+	}
+	if t == "" {
+		return "any"
+	}
+	return t
+}
+
+func (d *swaggerDiff) diffEnum(location string, a, b []interface{}) {
+	if len(a) == 0 && len(b) > 0 {
+		d.add(Breaking, location, "enum added")
+		return
+	}
+	if len(b) == 0 {
+		if len(a) > 0 {
+			d.add(NonBreaking, location, "enum removed")
+		}
+		return
+	}
+	for _, each := range a {
+		if !containsValue(b, each) {
+			d.add(Breaking, location, "enum value %v removed", each)
+		}
+	}
+	for _, each := range b {
+		if !containsValue(a, each) {
+			d.add(NonBreaking, location, "enum value %v added", each)
+		}
+	}
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, each := range values {
+		if reflect.DeepEqual(each, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *swaggerDiff) diffResponses(location string, a, b *spec.Responses) {
+	if a == nil || b == nil {
+		return
+	}
+	for code, rA := range a.StatusCodeResponses {
+		at := fmt.Sprintf("%s response %d", location, code)
+		rB, ok := b.StatusCodeResponses[code]
+		if !ok {
+			if code >= http.StatusOK && code < http.StatusMultipleChoices {
+				d.add(Breaking, at, "response removed")
+			} else {
+				d.add(NonBreaking, at, "response removed")
+			}
+			continue
+		}
+		if typeA, typeB := schemaType(rA.Schema), schemaType(rB.Schema); typeA != typeB {
+			d.add(Breaking, at, "type changed from %s to %s", typeA, typeB)
+		}
+	}
+	for code := range b.StatusCodeResponses {
+		if _, ok := a.StatusCodeResponses[code]; !ok {
+			d.add(NonBreaking, fmt.Sprintf("%s response %d", location, code), "response added")
+		}
+	}
+}
+
+func (d *swaggerDiff) diffDefinitions(a, b spec.Definitions) {
+	for name, defA := range a {
+		at := "definition " + name
+		defB, ok := b[name]
+		if !ok {
+			d.add(Breaking, at, "definition removed")
+			continue
+		}
+		if typeA, typeB := schemaType(&defA), schemaType(&defB); typeA != typeB {
+			d.add(Breaking, at, "type changed from %s to %s", typeA, typeB)
+		}
+		d.diffEnum(at, defA.Enum, defB.Enum)
+		for prop, propA := range defA.Properties {
+			propAt := at + " property " + prop
+			propB, ok := defB.Properties[prop]
+			if !ok {
+				d.add(Breaking, propAt, "property removed")
+				continue
+			}
+			if typeA, typeB := schemaType(&propA), schemaType(&propB); typeA != typeB {
+				d.add(Breaking, propAt, "type changed from %s to %s", typeA, typeB)
+			}
+			d.diffEnum(propAt, propA.Enum, propB.Enum)
+		}
+		for prop := range defB.Properties {
+			if _, ok := defA.Properties[prop]; ok {
+				continue
+			}
+			if containsString(defB.Required, prop) {
+				d.add(Breaking, at+" property "+prop, "required property added")
+			} else {
+				d.add(NonBreaking, at+" property "+prop, "optional property added")
+			}
+		}
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			d.add(NonBreaking, "definition "+name, "definition added")
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, each := range values {
+		if each == value {
+			return true
+		}
+	}
+	return false
+}
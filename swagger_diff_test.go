@@ -0,0 +1,128 @@
+package restfulspec
+
+import (
+	"reflect"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+)
+
+type Pet struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind" enum:"cat|dog|bird"`
+	Age     int    `json:"age"`
+	Comment string `json:"comment"`
+}
+
+type PetV2 struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind" enum:"cat|dog|fish"`
+	Age   string `json:"age"`
+	Owner string `json:"owner,omitempty"`
+}
+
+func TestDiffSwagger(t *testing.T) {
+	ws1 := new(restful.WebService)
+	ws1.Path("/pets")
+	ws1.Route(ws1.GET("").To(dummy).
+		Param(ws1.QueryParameter("limit", "max results").DataType("integer")).
+		Param(ws1.QueryParameter("sort", "order").PossibleValues([]string{"asc", "desc"})).
+		Writes([]Pet{}))
+	ws1.Route(ws1.DELETE("/{name}").To(dummy).
+		Param(ws1.PathParameter("name", "pet name")))
+	old := BuildSwagger(Config{WebServices: []*restful.WebService{ws1}})
+
+	ws2 := new(restful.WebService)
+	ws2.Path("/pets")
+	ws2.Route(ws2.GET("").To(dummy).
+		Param(ws2.QueryParameter("limit", "max results").DataType("string")).
+		Param(ws2.QueryParameter("sort", "order").PossibleValues([]string{"asc"})).
+		Param(ws2.QueryParameter("owner", "owner name")).
+		Writes([]Pet{}))
+	ws2.Route(ws2.POST("").To(dummy).Reads(Pet{}))
+	current := BuildSwagger(Config{WebServices: []*restful.WebService{ws2}})
+
+	changes := DiffSwagger(old, current)
+	for _, each := range changes {
+		t.Log(each)
+	}
+	expected := map[string]ChangeKind{
+		"/pets/{name}":                    Breaking,
+		"GET /pets parameter query:limit": Breaking,
+		"GET /pets parameter query:sort":  Breaking,
+		"GET /pets parameter query:owner": NonBreaking,
+		"POST /pets":                      NonBreaking,
+	}
+	if got, want := len(changes), len(expected); got != want {
+		t.Fatalf("got %v changes want %v", got, want)
+	}
+	for _, each := range changes {
+		kind, ok := expected[each.Location]
+		if !ok {
+			t.Errorf("unexpected change %v", each)
+			continue
+		}
+		if got, want := each.Kind, kind; got != want {
+			t.Errorf("%s: got %v want %v", each.Location, got, want)
+		}
+	}
+	if !HasBreakingChanges(changes) {
+		t.Errorf("expected breaking changes")
+	}
+	if got, want := len(DiffSwagger(old, old)), 0; got != want {
+		t.Errorf("got %v changes want %v", got, want)
+	}
+}
+
+func TestDiffSwaggerDefinitions(t *testing.T) {
+	old := BuildSwagger(Config{})
+	old.Definitions = definitionsFromStruct(Pet{})
+	current := BuildSwagger(Config{})
+	current.Definitions = definitionsFromStructWithConfig(PetV2{}, Config{
+		ModelTypeNameHandler: func(t reflect.Type) (string, bool) { return "restfulspec.Pet", true },
+	})
+
+	changes := DiffSwagger(old, current)
+	for _, each := range changes {
+		t.Log(each)
+	}
+	breaking, nonBreaking := 0, 0
+	for _, each := range changes {
+		if each.Kind == Breaking {
+			breaking++
+		} else {
+			nonBreaking++
+		}
+	}
+	// removed comment, changed type of age, removed enum value bird
+	if got, want := breaking, 3; got != want {
+		t.Errorf("got %v breaking changes want %v", got, want)
+	}
+	// added enum value fish, added optional owner
+	if got, want := nonBreaking, 2; got != want {
+		t.Errorf("got %v non-breaking changes want %v", got, want)
+	}
+	// changes of the same location are ordered by kind and message
+	for i := 1; i < len(changes); i++ {
+		prev, each := changes[i-1], changes[i]
+		if prev.Location == each.Location && (prev.Kind > each.Kind || prev.Kind == each.Kind && prev.Message > each.Message) {
+			t.Errorf("%v before %v", prev, each)
+		}
+	}
+}
+
+func TestDiffSwaggerNil(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/pets").To(dummy).Writes([]Pet{}))
+	current := BuildSwagger(Config{WebServices: []*restful.WebService{ws}})
+
+	if changes := DiffSwagger(nil, current); len(changes) == 0 || HasBreakingChanges(changes) {
+		t.Errorf("got %v want non-breaking additions", changes)
+	}
+	if changes := DiffSwagger(current, nil); !HasBreakingChanges(changes) {
+		t.Errorf("got %v want breaking removals", changes)
+	}
+	if changes := DiffSwagger(nil, nil); len(changes) != 0 {
+		t.Errorf("got %v want none", changes)
+	}
+}
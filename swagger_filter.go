@@ -0,0 +1,144 @@
+package restfulspec
+
+import (
+	"sort"
+
+	"github.com/go-openapi/spec"
+)
+
+// FilterSwagger returns a copy of the swagger with only the operations that have one of the tags of the tagFilter.
+// Path items without operations and definitions that are no longer referenced are removed, as are the tags
+// that are not in the tagFilter. The swagger itself is not modified.
+func FilterSwagger(swagger *spec.Swagger, tagFilter []string) *spec.Swagger {
+	filtered := filterSwaggerByTags(swagger, tagFilter)
+	clone, err := cloneSwagger(filtered)
+	if err != nil {
+		warn(Config{}, "copy of filtered swagger failed", "error", err)
+		return filtered
+	}
+	return clone
+}
+
+// BuildSwaggerPerTag returns a Swagger object for each tag of the operations of all services.
+// Each holds the operations with that tag and the definitions they refer to, directly or indirectly.
+// Operations without tags are in none of them.
+func BuildSwaggerPerTag(config Config) map[string]*spec.Swagger {
+	return splitSwaggerByTag(BuildSwagger(config))
+}
+
+// splitSwaggerByTag returns the FilterSwagger copy of the swagger for each tag of its operations,
+// so the documents share no operations or path items with each other or with the swagger.
+func splitSwaggerByTag(swagger *spec.Swagger) map[string]*spec.Swagger {
+	docs := map[string]*spec.Swagger{}
+	for _, tag := range operationTags(swagger) {
+		docs[tag] = FilterSwagger(swagger, []string{tag})
+	}
+	return docs
+}
+
+// operationTags returns the sorted tags of all operations.
+func operationTags(swagger *spec.Swagger) []string {
+	seen := map[string]bool{}
+	for _, item := range pathsOf(swagger) {
+		for _, op := range pathItemOperations(&item) {
+			for _, tag := range op.Tags {
+				seen[tag] = true
+			}
+		}
+	}
+	tags := make([]string, 0, len(seen))
+	for tag := range seen {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// filterSwaggerByTags returns a copy of the swagger with only the operations that have one of the tags,
+// the path items that still have operations and the definitions that are referenced.
+// The swagger itself is not modified.
+func filterSwaggerByTags(swagger *spec.Swagger, tags []string) *spec.Swagger {
+	filtered := new(spec.Swagger)
+	*filtered = *swagger
+	filtered.Paths = &spec.Paths{VendorExtensible: pathsVendorExtensible(swagger), Paths: map[string]spec.PathItem{}}
+	for path, item := range pathsOf(swagger) {
+		kept := false
+		for method, op := range pathItemOperationsByMethod(item) {
+			if hasAnyTag(op, tags) {
+				kept = true
+			} else {
+				setPathItemOperation(&item, method, nil)
+			}
+		}
+		if kept {
+			filtered.Paths.Paths[path] = item
+		}
+	}
+	filtered.Tags = nil
+	for _, each := range swagger.Tags {
+		if containsString(tags, each.Name) {
+			filtered.Tags = append(filtered.Tags, each)
+		}
+	}
+	filtered.Definitions = referencedDefinitions(filtered)
+	return filtered
+}
+
+func pathsVendorExtensible(swagger *spec.Swagger) spec.VendorExtensible {
+	if swagger.Paths == nil {
+		return spec.VendorExtensible{}
+	}
+	return swagger.Paths.VendorExtensible
+}
+
+func hasAnyTag(op *spec.Operation, tags []string) bool {
+	for _, each := range op.Tags {
+		if containsString(tags, each) {
+			return true
+		}
+	}
+	return false
+}
+
+// referencedDefinitions returns the definitions that the operations, global parameters and global responses
+// of the swagger refer to, directly or indirectly.
+func referencedDefinitions(swagger *spec.Swagger) spec.Definitions {
+	collected := spec.Definitions{}
+	walkOperationSchemas(swagger, func(s *spec.Schema) {
+		addReferencedDefinitions(s, swagger.Definitions, collected)
+	})
+	return collected
+}
+
+// walkOperationSchemas calls the function with the schema of each parameter and response of the operations,
+// path items, global parameters and global responses of the swagger. The schema can be nil.
+func walkOperationSchemas(swagger *spec.Swagger, add func(s *spec.Schema)) {
+	addResponse := func(r *spec.Response) {
+		if r != nil {
+			add(r.Schema)
+		}
+	}
+	for _, item := range pathsOf(swagger) {
+		for _, each := range item.Parameters {
+			add(each.Schema)
+		}
+		for _, op := range pathItemOperations(&item) {
+			for _, each := range op.Parameters {
+				add(each.Schema)
+			}
+			if op.Responses == nil {
+				continue
+			}
+			addResponse(op.Responses.Default)
+			for _, each := range op.Responses.StatusCodeResponses {
+				addResponse(&each)
+			}
+		}
+	}
+	for _, each := range swagger.Parameters {
+		add(each.Schema)
+	}
+	for _, each := range swagger.Responses {
+		addResponse(&each)
+	}
+}
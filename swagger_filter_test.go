@@ -0,0 +1,149 @@
+package restfulspec
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/go-openapi/spec"
+)
+
+type Keeper struct {
+	Name string
+}
+
+type Kennel struct {
+	Name   string
+	Keeper Keeper
+}
+
+func taggedWebService() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/pets").To(dummy).Metadata(KeyOpenAPITags, []string{"pets"}).Returns(200, "ok", []Kennel{}))
+	ws.Route(ws.POST("/pets").To(dummy).Metadata(KeyOpenAPITags, []string{"pets", "admin"}).Reads(Kennel{}))
+	ws.Route(ws.GET("/items").To(dummy).Metadata(KeyOpenAPITags, []string{"items"}).Returns(200, "ok", Item{}))
+	ws.Route(ws.GET("/version").To(dummy))
+	return ws
+}
+
+func TestBuildSwaggerPerTag(t *testing.T) {
+	docs := BuildSwaggerPerTag(Config{
+		WebServices: []*restful.WebService{taggedWebService()},
+		PostBuildSwaggerObjectHandler: func(s *spec.Swagger) {
+			s.Tags = []spec.Tag{{TagProps: spec.TagProps{Name: "pets"}}, {TagProps: spec.TagProps{Name: "items"}}}
+		},
+	})
+	if got, want := len(docs), 3; got != want {
+		t.Fatalf("got %d docs want %d", got, want)
+	}
+
+	pets := docs["pets"]
+	if got, want := len(pets.Paths.Paths), 1; got != want {
+		t.Errorf("got %d paths want %d", got, want)
+	}
+	if item := pets.Paths.Paths["/pets"]; item.Get == nil || item.Post == nil {
+		t.Errorf("missing operations: %s", asJSON(item))
+	}
+	if _, ok := pets.Definitions["restfulspec.Keeper"]; !ok || len(pets.Definitions) != 2 {
+		t.Errorf("got definitions %v", pets.Definitions)
+	}
+	if len(pets.Tags) != 1 || pets.Tags[0].Name != "pets" {
+		t.Errorf("got tags %v", pets.Tags)
+	}
+
+	admin := docs["admin"]
+	if item := admin.Paths.Paths["/pets"]; item.Get != nil || item.Post == nil {
+		t.Errorf("got operations: %s", asJSON(item))
+	}
+
+	items := docs["items"]
+	if _, ok := items.Definitions["restfulspec.Item"]; !ok || len(items.Definitions) != 1 {
+		t.Errorf("got definitions %v", items.Definitions)
+	}
+}
+
+func TestSplitSwaggerByTagCopies(t *testing.T) {
+	swagger := BuildSwagger(Config{WebServices: []*restful.WebService{taggedWebService()}})
+	docs := splitSwaggerByTag(swagger)
+
+	post := docs["admin"].Paths.Paths["/pets"].Post
+	post.Summary = "changed"
+	post.Parameters[0].Name = "changed"
+	docs["admin"].Definitions["restfulspec.Kennel"].Properties["Name"].Type[0] = "changed"
+
+	for name, doc := range map[string]*spec.Swagger{"source": swagger, "pets": docs["pets"]} {
+		post := doc.Paths.Paths["/pets"].Post
+		if post.Summary == "changed" || post.Parameters[0].Name == "changed" {
+			t.Errorf("%s: operation changed: %s", name, asJSON(post))
+		}
+		if got := doc.Definitions["restfulspec.Kennel"].Properties["Name"].Type[0]; got == "changed" {
+			t.Errorf("%s: definition changed", name)
+		}
+	}
+}
+
+func TestSplitByTag(t *testing.T) {
+	ws := NewOpenAPIService(Config{APIPath: "/apidocs.json", SplitByTag: true, WebServices: []*restful.WebService{taggedWebService()}})
+	wc := restful.NewContainer().Add(ws)
+
+	recorder := httptest.NewRecorder()
+	wc.Dispatch(recorder, httptest.NewRequest("GET", "/apidocs.json/items", nil))
+	var items spec.Swagger
+	if err := json.Unmarshal(recorder.Body.Bytes(), &items); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := items.Paths.Paths["/items"]; !ok || len(items.Paths.Paths) != 1 {
+		t.Errorf("got paths %v", items.Paths.Paths)
+	}
+
+	recorder = httptest.NewRecorder()
+	wc.Dispatch(recorder, httptest.NewRequest("GET", "/apidocs.json/unknown", nil))
+	if recorder.Code != 404 {
+		t.Errorf("got status %d", recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	wc.Dispatch(recorder, httptest.NewRequest("GET", "/apidocs.json", nil))
+	var all spec.Swagger
+	if err := json.Unmarshal(recorder.Body.Bytes(), &all); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(all.Paths.Paths), 3; got != want {
+		t.Errorf("got %d paths want %d", got, want)
+	}
+}
+
+func TestFilterSwagger(t *testing.T) {
+	swagger := BuildSwagger(Config{WebServices: []*restful.WebService{taggedWebService()}})
+	swagger.Definitions["restfulspec.Unused"] = *spec.StringProperty()
+
+	filtered := FilterSwagger(swagger, []string{"items", "admin"})
+	if got, want := len(filtered.Paths.Paths), 2; got != want {
+		t.Errorf("got %d paths want %d", got, want)
+	}
+	if item := filtered.Paths.Paths["/pets"]; item.Get != nil || item.Post == nil {
+		t.Errorf("got operations: %s", asJSON(item))
+	}
+	if _, ok := filtered.Paths.Paths["/version"]; ok {
+		t.Error("untagged path should be removed")
+	}
+	if _, ok := filtered.Definitions["restfulspec.Unused"]; ok {
+		t.Error("unreferenced definition should be removed")
+	}
+	if got, want := len(filtered.Definitions), 3; got != want {
+		t.Errorf("got %d definitions want %d", got, want)
+	}
+
+	// the original is not modified, also not by changing the copy
+	filtered.Paths.Paths["/pets"].Post.Summary = "changed"
+	if got, want := len(swagger.Paths.Paths), 3; got != want {
+		t.Errorf("got %d paths want %d", got, want)
+	}
+	if item := swagger.Paths.Paths["/pets"]; item.Get == nil || item.Post.Summary == "changed" {
+		t.Errorf("original changed: %s", asJSON(item))
+	}
+	if _, ok := swagger.Definitions["restfulspec.Unused"]; !ok {
+		t.Error("original definitions changed")
+	}
+}
@@ -0,0 +1,98 @@
+package restfulspec
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/go-openapi/spec"
+)
+
+// SwaggerHandlerConfig configures the handler of NewSwaggerHandler.
+type SwaggerHandlerConfig struct {
+	// [optional] Origins from which browsers may read the document, such as that of a Swagger UI
+	//   deployed elsewhere. "*" allows any origin. No CORS headers are set if empty.
+	AllowedOrigins []string
+	// [optional] Value of the Cache-Control header of the document, e.g. "public, max-age=3600". None if empty.
+	CacheControl string
+	// [optional] If set, the JSON document is indented.
+	PrettyPrint bool
+}
+
+// NewSwaggerHandler returns a http.Handler that serves the JSON document of the Swagger object,
+// for use outside a restful.Container. The document is marshaled once; requests with a matching
+// If-None-Match header get 304 Not Modified. It answers CORS preflight requests of the AllowedOrigins.
+func NewSwaggerHandler(swagger *spec.Swagger, config SwaggerHandlerConfig) http.Handler {
+	h := swaggerHandler{config: config}
+	if config.PrettyPrint {
+		h.body, h.err = json.MarshalIndent(swaggerDocument(swagger), "", " ")
+	} else {
+		h.body, h.err = json.Marshal(swaggerDocument(swagger))
+	}
+	h.etag = etagOf(h.body)
+	return h
+}
+
+type swaggerHandler struct {
+	config SwaggerHandlerConfig
+	body   []byte
+	etag   string
+	err    error
+}
+
+func (h swaggerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.setCORSHeaders(w, r)
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodOptions:
+		w.Header().Set("Allow", "GET, HEAD, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		w.Header().Set("Allow", "GET, HEAD, OPTIONS")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if h.err != nil {
+		http.Error(w, h.err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("ETag", h.etag)
+	if h.config.CacheControl != "" {
+		w.Header().Set("Cache-Control", h.config.CacheControl)
+	}
+	if etagMatches(r.Header.Get("If-None-Match"), h.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(h.body)
+	}
+}
+
+// setCORSHeaders allows the origin of the request if it is one of the AllowedOrigins.
+func (h swaggerHandler) setCORSHeaders(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get(restful.HEADER_Origin)
+	if origin == "" {
+		return
+	}
+	for _, each := range h.config.AllowedOrigins {
+		if each != "*" && !strings.EqualFold(each, origin) {
+			continue
+		}
+		if each == "*" {
+			w.Header().Set(restful.HEADER_AccessControlAllowOrigin, "*")
+		} else {
+			w.Header().Set(restful.HEADER_AccessControlAllowOrigin, origin)
+			// the response differs by origin
+			w.Header().Add("Vary", restful.HEADER_Origin)
+		}
+		w.Header().Set(restful.HEADER_AccessControlAllowMethods, "GET, HEAD, OPTIONS")
+		w.Header().Set(restful.HEADER_AccessControlAllowHeaders, "If-None-Match")
+		w.Header().Set(restful.HEADER_AccessControlExposeHeaders, "ETag")
+		return
+	}
+}
@@ -0,0 +1,70 @@
+package restfulspec
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/spec"
+)
+
+func TestNewSwaggerHandler(t *testing.T) {
+	swagger := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Swagger: "2.0", Info: &spec.Info{InfoProps: spec.InfoProps{Title: "Handled"}}}}
+	handler := NewSwaggerHandler(swagger, SwaggerHandlerConfig{
+		AllowedOrigins: []string{"https://docs.example.com"},
+		CacheControl:   "public, max-age=60",
+		PrettyPrint:    true,
+	})
+	serve := func(method, origin, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/apidocs.json", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "https://docs.example.com", "")
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	if got, want := rec.Header().Get("Access-Control-Allow-Origin"), "https://docs.example.com"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := rec.Header().Get("Cache-Control"), "public, max-age=60"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := rec.Header().Get("Content-Type"), "application/json"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "\n \"info\": {") {
+		t.Errorf("expected indented JSON, got %s", body)
+	}
+
+	if got := serve(http.MethodGet, "https://evil.example.com", "").Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("got %v want none", got)
+	}
+	if got, want := serve(http.MethodOptions, "https://docs.example.com", "").Code, http.StatusNoContent; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := serve(http.MethodPost, "", "").Code, http.StatusMethodNotAllowed; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := serve(http.MethodGet, "", rec.Header().Get("ETag")).Code, http.StatusNotModified; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	wildcard := NewSwaggerHandler(swagger, SwaggerHandlerConfig{AllowedOrigins: []string{"*"}})
+	req := httptest.NewRequest(http.MethodGet, "/apidocs.json", nil)
+	req.Header.Set("Origin", "https://other.example.com")
+	rec = httptest.NewRecorder()
+	wildcard.ServeHTTP(rec, req)
+	if got, want := rec.Header().Get("Access-Control-Allow-Origin"), "*"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package restfulspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+	"gopkg.in/yaml.v3"
+)
+
+// WriteSwaggerJSON writes the indented JSON document of the Swagger object, using the marshaler of the spec package.
+func WriteSwaggerJSON(w io.Writer, swagger *spec.Swagger) error {
+	data, err := json.MarshalIndent(swaggerDocument(swagger), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteSwaggerYAML writes the YAML document of the Swagger object, keeping the field order of its JSON document.
+func WriteSwaggerYAML(w io.Writer, swagger *spec.Swagger) error {
+	data, err := marshalSwaggerYAML(swagger)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func marshalSwaggerYAML(swagger *spec.Swagger) ([]byte, error) {
+	return marshalYAML(swaggerDocument(swagger))
+}
+
+// marshalYAML returns the YAML document of the JSON of the value, keeping its field order.
+func marshalYAML(value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is YAML; a node keeps the canonical field order
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	clearNodeStyle(&doc)
+	buf := new(bytes.Buffer)
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), enc.Close()
+}
+
+// clearNodeStyle drops the flow and quoting styles of a node decoded from JSON, to get block style YAML.
+func clearNodeStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, each := range node.Content {
+		clearNodeStyle(each)
+	}
+}
+
+// LoadBaseSpec reads a Swagger document from a JSON or YAML file, e.g. to use as Config.BaseSpec.
+// Files with a .yaml or .yml extension are YAML, files with a .json extension are JSON;
+// otherwise the format is detected from the content.
+func LoadBaseSpec(path string) (*spec.Swagger, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseBaseSpec(path, data)
+}
+
+// LoadBaseSpecFS reads a Swagger document from a JSON or YAML file of the file system, such as an embed.FS,
+// see LoadBaseSpec and Config.BaseSpecFS.
+func LoadBaseSpecFS(fsys fs.FS, path string) (*spec.Swagger, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	return parseBaseSpec(path, data)
+}
+
+func parseBaseSpec(path string, data []byte) (*spec.Swagger, error) {
+	if isYAMLDocument(path, data) {
+		doc, err := swag.BytesToYAMLDoc(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse YAML of %s: %v", path, err)
+		}
+		if data, err = swag.YAMLToJSON(doc); err != nil {
+			return nil, fmt.Errorf("convert YAML of %s: %v", path, err)
+		}
+	}
+	swagger := new(spec.Swagger)
+	if err := json.Unmarshal(data, swagger); err != nil {
+		return nil, fmt.Errorf("parse JSON of %s: %v", path, err)
+	}
+	return swagger, nil
+}
+
+func isYAMLDocument(path string, data []byte) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	case ".json":
+		return false
+	}
+	return !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
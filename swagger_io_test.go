@@ -0,0 +1,139 @@
+package restfulspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+)
+
+func sampleSwagger() *spec.Swagger {
+	ws := new(restful.WebService)
+	ws.Path("/samples")
+	ws.Route(ws.GET("/{id}").To(dummy).
+		Param(ws.PathParameter("id", "sample id")).
+		Returns(200, "ok", Sample{}).
+		Returns(404, "not found", nil))
+	return BuildSwagger(Config{WebServices: []*restful.WebService{ws}, Host: "localhost"})
+}
+
+func TestWriteSwaggerJSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteSwaggerJSON(buf, sampleSwagger()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "{\n  \"swagger\": \"2.0\"") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+	var decoded spec.Swagger
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := decoded.Paths.Paths["/samples/{id}"]; !ok {
+		t.Errorf("missing path")
+	}
+}
+
+func TestWriteSwaggerYAML(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteSwaggerYAML(buf, sampleSwagger()); err != nil {
+		t.Fatal(err)
+	}
+	t.Log(buf.String())
+	if !strings.HasPrefix(buf.String(), "swagger: \"2.0\"\nhost: localhost\n") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+	doc, err := swag.BytesToYAMLDoc(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := swag.YAMLToJSON(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded spec.Swagger
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := decoded.Paths.Paths["/samples/{id}"].Get.Responses.StatusCodeResponses[404]; !ok {
+		t.Errorf("missing response")
+	}
+}
+
+func TestLoadBaseSpec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restfulspec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	yamlDoc := "swagger: \"2.0\"\ninfo:\n  title: Base\n  version: \"1\"\n  x-audience: partners\npaths:\n  /health:\n    get:\n      responses:\n        200:\n          description: ok\n"
+	jsonDoc := `{"swagger":"2.0","info":{"title":"Base","version":"1","x-audience":"partners"},"paths":{"/health":{"get":{"responses":{"200":{"description":"ok"}}}}}}`
+	for name, content := range map[string]string{
+		"base.yaml": yamlDoc,
+		"base.yml":  yamlDoc,
+		"base.json": jsonDoc,
+		"base.spec": yamlDoc,
+		"base":      jsonDoc,
+	} {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		s, err := LoadBaseSpec(path)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if s.Info.Title != "Base" || s.Info.Extensions["x-audience"] != "partners" {
+			t.Errorf("%s: got info %v", name, s.Info)
+		}
+		if _, ok := s.Paths.Paths["/health"].Get.Responses.StatusCodeResponses[200]; !ok {
+			t.Errorf("%s: missing response", name)
+		}
+	}
+
+	if _, err := LoadBaseSpec(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := ioutil.WriteFile(invalid, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBaseSpec(invalid); err == nil {
+		t.Errorf("expected error for invalid file")
+	}
+}
+
+func TestBaseSpecFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"api/swagger.yaml": {Data: []byte("swagger: \"2.0\"\ninfo:\n  title: Embedded\n  version: \"1\"\npaths:\n  /health:\n    get:\n      responses:\n        200:\n          description: ok\n")},
+	}
+	ws := new(restful.WebService)
+	ws.Path("/samples")
+	ws.Route(ws.GET("/").To(dummy))
+	swagger, err := BuildSwaggerWithError(Config{WebServices: []*restful.WebService{ws}, BaseSpecFS: fsys, BaseSpecPath: "api/swagger.yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := swagger.Info.Title, "Embedded"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	for _, path := range []string{"/health", "/samples"} {
+		if _, ok := swagger.Paths.Paths[path]; !ok {
+			t.Errorf("missing %s in %v", path, swagger.Paths.Paths)
+		}
+	}
+
+	if _, err := BuildSwaggerWithError(Config{WebServices: []*restful.WebService{ws}, BaseSpecFS: fsys, BaseSpecPath: "missing.json"}); err == nil {
+		t.Error("expected error for missing base spec")
+	}
+}
@@ -0,0 +1,111 @@
+package restfulspec
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/go-openapi/spec"
+)
+
+// MergedFromExtension is the vendor extension that MergeSwagger adds to each operation of the overlay.
+// It holds the title of the overlay, or its host and basePath if it has no title.
+const MergedFromExtension = "x-merged-from"
+
+// MergeSwagger returns a copy of the base with the paths, definitions and tags of the overlay added.
+// It returns an error if both have an operation for the same path and method, or a different definition
+// with the same name. Neither base nor overlay is modified.
+func MergeSwagger(base, overlay *spec.Swagger) (*spec.Swagger, error) {
+	return MergeSwaggerWithConfig(base, overlay, Config{})
+}
+
+// MergeSwaggerWithConfig is MergeSwagger in which operations of the overlay replace those of the base
+// if the config has AllowPathMergeConflicts set.
+func MergeSwaggerWithConfig(base, overlay *spec.Swagger, config Config) (*spec.Swagger, error) {
+	merged, err := cloneSwagger(base)
+	if err != nil {
+		return nil, fmt.Errorf("copy base: %v", err)
+	}
+	other, err := cloneSwagger(overlay)
+	if err != nil {
+		return nil, fmt.Errorf("copy overlay: %v", err)
+	}
+	if merged.Paths == nil {
+		merged.Paths = &spec.Paths{}
+	}
+	if merged.Paths.Paths == nil {
+		merged.Paths.Paths = map[string]spec.PathItem{}
+	}
+	source := mergeSource(other)
+	otherPaths := pathsOf(other)
+	for _, path := range sortedPaths(otherPaths) {
+		item := otherPaths[path]
+		existing, ok := merged.Paths.Paths[path]
+		ops := pathItemOperationsByMethod(item)
+		for _, method := range sortedMethods(ops) {
+			op := ops[method]
+			if ok && pathItemOperationsByMethod(existing)[method] != nil && !config.AllowPathMergeConflicts {
+				return nil, fmt.Errorf("operation %s %s is in both base and overlay", method, path)
+			}
+			setExtension(&op.VendorExtensible, MergedFromExtension, source)
+			setPathItemOperation(&existing, method, op)
+		}
+		if len(item.Parameters) > 0 {
+			existing.Parameters = item.Parameters
+		}
+		for key, value := range item.Extensions {
+			setExtension(&existing.VendorExtensible, key, value)
+		}
+		merged.Paths.Paths[path] = existing
+	}
+	if merged.Definitions == nil && len(other.Definitions) > 0 {
+		merged.Definitions = spec.Definitions{}
+	}
+	for name, def := range other.Definitions {
+		if existing, ok := merged.Definitions[name]; ok && !reflect.DeepEqual(existing, def) {
+			return nil, fmt.Errorf("definition %q is different in base and overlay", name)
+		}
+		merged.Definitions[name] = def
+	}
+	for _, each := range other.Tags {
+		if !hasTag(merged.Tags, each.Name) {
+			merged.Tags = append(merged.Tags, each)
+		}
+	}
+	return merged, nil
+}
+
+// mergeSource returns the value of the MergedFromExtension for the operations of the swagger.
+func mergeSource(swagger *spec.Swagger) string {
+	if swagger.Info != nil && swagger.Info.Title != "" {
+		return swagger.Info.Title
+	}
+	return swagger.Host + swagger.BasePath
+}
+
+func sortedPaths(paths map[string]spec.PathItem) []string {
+	keys := make([]string, 0, len(paths))
+	for k := range paths {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedMethods(ops map[string]*spec.Operation) []string {
+	keys := make([]string, 0, len(ops))
+	for k := range ops {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func hasTag(tags []spec.Tag, name string) bool {
+	for _, each := range tags {
+		if each.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package restfulspec
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+)
+
+func mergeTestSwagger(title, path string, definitions spec.Definitions) *spec.Swagger {
+	return &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Swagger: "2.0",
+		Info:    &spec.Info{InfoProps: spec.InfoProps{Title: title}},
+		Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+			path: {PathItemProps: spec.PathItemProps{Get: &spec.Operation{OperationProps: spec.OperationProps{ID: title}}}},
+		}},
+		Definitions: definitions,
+		Tags:        []spec.Tag{{TagProps: spec.TagProps{Name: title}}},
+	}}
+}
+
+func TestMergeSwagger(t *testing.T) {
+	shared := spec.Definitions{"Shared": *spec.StringProperty()}
+	base := mergeTestSwagger("users", "/users", shared)
+	overlay := mergeTestSwagger("orders", "/orders", spec.Definitions{"Shared": *spec.StringProperty(), "Order": *spec.Int64Property()})
+	overlay.Paths.Paths["/users"] = spec.PathItem{PathItemProps: spec.PathItemProps{Post: new(spec.Operation)}}
+
+	merged, err := MergeSwagger(base, overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(merged.Paths.Paths), 2; got != want {
+		t.Errorf("got %d paths want %d", got, want)
+	}
+	users := merged.Paths.Paths["/users"]
+	if users.Get == nil || users.Post == nil {
+		t.Errorf("got %s", asJSON(users))
+	}
+	if _, ok := users.Get.Extensions[MergedFromExtension]; ok {
+		t.Error("operation of base should not be marked as merged")
+	}
+	if got, want := users.Post.Extensions[MergedFromExtension], "orders"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got, want := len(merged.Definitions), 2; got != want {
+		t.Errorf("got %d definitions want %d", got, want)
+	}
+	if got, want := len(merged.Tags), 2; got != want {
+		t.Errorf("got %d tags want %d", got, want)
+	}
+
+	// inputs are not modified
+	if got, want := len(base.Paths.Paths), 1; got != want {
+		t.Errorf("got %d paths want %d", got, want)
+	}
+	if _, ok := overlay.Paths.Paths["/users"].Post.Extensions[MergedFromExtension]; ok {
+		t.Error("overlay should not be modified")
+	}
+}
+
+func TestMergeSwaggerConflicts(t *testing.T) {
+	base := mergeTestSwagger("users", "/users", nil)
+	overlay := mergeTestSwagger("accounts", "/users", nil)
+	if _, err := MergeSwagger(base, overlay); err == nil {
+		t.Error("expected path conflict error")
+	}
+	merged, err := MergeSwaggerWithConfig(base, overlay, Config{AllowPathMergeConflicts: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := merged.Paths.Paths["/users"].Get.ID, "accounts"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	base = mergeTestSwagger("users", "/users", spec.Definitions{"Shared": *spec.StringProperty()})
+	overlay = mergeTestSwagger("orders", "/orders", spec.Definitions{"Shared": *spec.Int64Property()})
+	if _, err := MergeSwagger(base, overlay); err == nil {
+		t.Error("expected definition conflict error")
+	}
+}
@@ -0,0 +1,88 @@
+package restfulspec
+
+import (
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	restful "github.com/emicklei/go-restful/v3"
+)
+
+const (
+	// DefaultSwaggerUIPrefix is the path of the Swagger UI if Config.SwaggerUIPrefix is empty.
+	DefaultSwaggerUIPrefix = "/swagger-ui"
+	// DefaultSwaggerUITitle is the title of the Swagger UI page if Config.SwaggerUITitle is empty.
+	DefaultSwaggerUITitle = "Swagger UI"
+)
+
+// swaggerUIAssets holds the page, the initializer and the bundle, preset and stylesheet of swagger-ui-dist 4.15.5
+// (Apache License 2.0, see swaggerui/LICENSE) so that the UI does not load anything from a CDN.
+//
+//go:embed swaggerui
+var swaggerUIAssets embed.FS
+
+var swaggerUIIndex = template.Must(template.ParseFS(swaggerUIAssets, "swaggerui/index.html"))
+
+// ServeSwaggerUI adds a WebService to the container that serves the Swagger UI on Config.SwaggerUIPrefix
+// and the document of all services, which the UI loads, on <prefix>/swagger.json. The document is the one
+// NewOpenAPIService serves for the config, always as JSON. If container is nil, restful.DefaultContainer is used;
+// if the config has no WebServices, the document describes those registered with the container so far.
+func ServeSwaggerUI(container *restful.Container, config Config) {
+	if container == nil {
+		container = restful.DefaultContainer
+	}
+	if len(config.WebServices) == 0 {
+		config.WebServices = container.RegisteredWebServices()
+	}
+	prefix := strings.TrimSuffix(config.SwaggerUIPrefix, "/")
+	if prefix == "" {
+		prefix = DefaultSwaggerUIPrefix
+	}
+	title := config.SwaggerUITitle
+	if title == "" {
+		title = DefaultSwaggerUITitle
+	}
+	assets, _ := fs.Sub(swaggerUIAssets, "swaggerui")
+	ui := swaggerUIResource{
+		title:  title,
+		prefix: prefix,
+		assets: http.StripPrefix(prefix, http.FileServer(http.FS(assets))),
+	}
+
+	ws := new(restful.WebService)
+	ws.Path(prefix)
+	if !config.DisableCORS {
+		ws.Filter(enableCORS)
+	}
+	resource := newSpecResource(specDocument(BuildSwagger(config), config), false)
+	ws.Route(ws.GET("/swagger.json").Produces(restful.MIME_JSON).To(resource.getSwagger).Metadata(KeyOpenAPIDisable, true))
+	ws.Route(ws.GET("/").To(ui.getIndex).Metadata(KeyOpenAPIDisable, true))
+	ws.Route(ws.GET("/{asset}").To(ui.getAsset).Metadata(KeyOpenAPIDisable, true))
+	container.Add(ws)
+}
+
+// swaggerUIResource is a REST resource to serve the Swagger UI.
+type swaggerUIResource struct {
+	title  string
+	prefix string
+	assets http.Handler
+}
+
+func (u swaggerUIResource) getIndex(req *restful.Request, resp *restful.Response) {
+	resp.AddHeader("Content-Type", "text/html; charset=utf-8")
+	swaggerUIIndex.Execute(resp, map[string]string{
+		"Title":   u.title,
+		"Prefix":  u.prefix,
+		"SpecURL": u.prefix + "/swagger.json",
+	})
+}
+
+func (u swaggerUIResource) getAsset(req *restful.Request, resp *restful.Response) {
+	if req.PathParameter("asset") == "index.html" {
+		u.getIndex(req, resp)
+		return
+	}
+	u.assets.ServeHTTP(resp, req.Request)
+}
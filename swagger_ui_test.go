@@ -0,0 +1,128 @@
+package restfulspec
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	restful "github.com/emicklei/go-restful/v3"
+	"github.com/go-openapi/spec"
+)
+
+func TestServeSwaggerUI(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/samples")
+	ws.Route(ws.GET("/").To(dummy).Returns(200, "ok", Sample{}))
+	container := restful.NewContainer()
+	container.Add(ws)
+	ServeSwaggerUI(container, Config{
+		WebServices:     container.RegisteredWebServices(),
+		SwaggerUITitle:  "Sample <API>",
+		SwaggerUIPrefix: "/docs/",
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		container.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: got status %d", path, rec.Code)
+		}
+		return rec
+	}
+
+	index := get("/docs/").Body.String()
+	if !strings.Contains(index, "<title>Sample &lt;API&gt;</title>") {
+		t.Errorf("missing title in %s", index)
+	}
+	if !strings.Contains(index, `data-url="/docs/swagger.json"`) {
+		t.Errorf("missing spec url in %s", index)
+	}
+	if get("/docs/index.html").Body.String() != index {
+		t.Errorf("index.html differs from index")
+	}
+	if js := get("/docs/swagger-initializer.js").Body.String(); !strings.Contains(js, "SwaggerUIBundle") {
+		t.Errorf("unexpected initializer %s", js)
+	}
+	if strings.Contains(index, "https://") {
+		t.Errorf("index loads from a CDN: %s", index)
+	}
+	for _, asset := range []string{"/docs/swagger-ui-bundle.js", "/docs/swagger-ui-standalone-preset.js", "/docs/swagger-ui.css"} {
+		if !strings.Contains(index, `"`+asset+`"`) {
+			t.Errorf("index does not load %s", asset)
+		}
+		if get(asset).Body.Len() == 0 {
+			t.Errorf("empty %s", asset)
+		}
+	}
+
+	var swagger spec.Swagger
+	if err := json.Unmarshal(get("/docs/swagger.json").Body.Bytes(), &swagger); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := swagger.Paths.Paths["/samples"]; !ok {
+		t.Errorf("missing path in %v", swagger.Paths.Paths)
+	}
+	if _, ok := swagger.Paths.Paths["/docs/swagger.json"]; ok {
+		t.Errorf("ui service is documented")
+	}
+}
+
+func TestServeSwaggerUIServesSpecDocument(t *testing.T) {
+	ws := new(restful.WebService)
+	ws.Path("/samples")
+	ws.Route(ws.GET("/").To(dummy).Returns(200, "ok", Sample{}))
+	config := Config{
+		WebServices:    []*restful.WebService{ws},
+		APIPath:        "/apidocs.json",
+		OpenAPIVersion: OpenAPIVersion31,
+	}
+
+	get := func(container *restful.Container, path string) string {
+		rec := httptest.NewRecorder()
+		container.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: got status %d", path, rec.Code)
+		}
+		return rec.Body.String()
+	}
+	ui := restful.NewContainer()
+	ServeSwaggerUI(ui, config)
+	api := restful.NewContainer()
+	api.Add(NewOpenAPIService(config))
+
+	got, want := get(ui, "/swagger-ui/swagger.json"), get(api, "/apidocs.json")
+	if got != want {
+		t.Errorf("got %s want %s", got, want)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(got), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc["openapi"] != "3.1.0" {
+		t.Errorf("not converted to OpenAPI 3.1: %s", got)
+	}
+}
+
+func TestServeSwaggerUIDefaultContainer(t *testing.T) {
+	saved := restful.DefaultContainer
+	defer func() { restful.DefaultContainer = saved }()
+	restful.DefaultContainer = restful.NewContainer()
+
+	ws := new(restful.WebService)
+	ws.Path("/samples")
+	ws.Route(ws.GET("/").To(dummy).Returns(200, "ok", Sample{}))
+	restful.DefaultContainer.Add(ws)
+	ServeSwaggerUI(nil, Config{})
+
+	rec := httptest.NewRecorder()
+	restful.DefaultContainer.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger-ui/swagger.json", nil))
+	var swagger spec.Swagger
+	if err := json.Unmarshal(rec.Body.Bytes(), &swagger); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := swagger.Paths.Paths["/samples"]; !ok {
+		t.Errorf("missing path in %v", swagger.Paths.Paths)
+	}
+}
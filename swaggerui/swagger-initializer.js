@@ -0,0 +1,11 @@
+window.onload = function () {
+  var element = document.getElementById("swagger-ui");
+  window.ui = SwaggerUIBundle({
+    url: element.dataset.url,
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};